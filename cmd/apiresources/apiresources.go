@@ -0,0 +1,75 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apiresources provides control to list supported resource types
+package apiresources
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+)
+
+// New creates a new instance of cobra.Command that prints supported resource types
+func New(storages storage.Registry) *cobra.Command {
+	var r = &cobra.Command{
+		Use:               "api-resources",
+		Short:             "Prints the supported resource types",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `Prints the supported resource types with their short names and supported verbs.
+Any of the names can be used as the type argument of the other commands.
+	`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var verbs, err = cmd.Flags().GetStringSlice("verbs")
+			if err != nil {
+				return err
+			}
+
+			var w = tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+			_, _ = fmt.Fprintln(w, "NAME\tSHORTNAMES\tVERBS")
+
+			for _, s := range storages.Storages() {
+				if !supportsAll(s, verbs) {
+					continue
+				}
+				var names []string
+				for _, v := range s.Verbs() {
+					names = append(names, string(v))
+				}
+				_, _ = fmt.Fprintf(w, "%v\t%v\t%v\n", s.Kind, strings.Join(s.ShortNames, ","), strings.Join(names, ","))
+			}
+
+			return w.Flush()
+		},
+	}
+	r.Flags().StringSlice("verbs", nil, "print only the types that support all the verbs, e.g. --verbs=list,watch")
+
+	return r
+}
+
+func supportsAll(s *storage.Storage, verbs []string) bool {
+	for _, v := range verbs {
+		if !s.Supports(storage.Verb(v)) {
+			return false
+		}
+	}
+	return true
+}
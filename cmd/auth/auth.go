@@ -0,0 +1,182 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth provides commands that show the identity nsmctl presents to NSM domains
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/nsmctl/pkg/client"
+)
+
+// New creates a new cobra.Command instance that allows to inspect the identity nsmctl presents to the current domain.
+// clients returns the client of the current domain configured by the common flags, the clients are closed by the caller.
+func New(clients func(ctx context.Context) (*client.Client, error)) *cobra.Command {
+	var r = &cobra.Command{
+		Use:               "auth",
+		Short:             "inspects the identity nsmctl presents to a NSM domain",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `Inspects the identity nsmctl presents to the current NSM domain, helps to debug policies of the domain.
+'nsmctl auth whoami' prints the X509-SVID, 'nsmctl auth token' mints the JWT sent with requests and prints its claims.
+	`,
+	}
+	r.AddCommand(newWhoamiCommand(clients), newTokenCommand(clients))
+	return r
+}
+
+func newWhoamiCommand(clients func(ctx context.Context) (*client.Client, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:               "whoami",
+		Short:             "prints the X509-SVID nsmctl presents to the domain",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `Prints the SPIFFE ID, the trust domain, the expiration and the certificate chain of the X509-SVID
+nsmctl presents to the servers of the current domain, e.g. 'nsmctl auth whoami --domain my-domain'.
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var c, err = clients(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			var svid, svidErr = c.SVID(cmd.Context())
+			if svidErr != nil {
+				return svidErr
+			}
+
+			var w = tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+			printField(w, "DOMAIN", c.Domain().Name)
+			printField(w, "SPIFFE ID", svid.ID.String())
+			printField(w, "TRUST DOMAIN", svid.ID.TrustDomain().String())
+			printField(w, "EXPIRES", expires(svid.Certificates[0].NotAfter))
+			printField(w, "SOURCE", c.IdentitySource())
+			if err = w.Flush(); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "CHAIN:")
+			w = tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+			_, _ = fmt.Fprintln(w, "  #\tSUBJECT\tISSUER\tNOT-AFTER")
+			for i, cert := range svid.Certificates {
+				_, _ = fmt.Fprintf(w, "  %v\t%v\t%v\t%v\n", i, subject(cert), cert.Issuer.String(), cert.NotAfter.UTC().Format(time.RFC3339))
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newTokenCommand(clients func(ctx context.Context) (*client.Client, error)) *cobra.Command {
+	var r = &cobra.Command{
+		Use:               "token",
+		Short:             "mints the JWT nsmctl sends with requests to the domain",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `Mints the JWT nsmctl sends with requests to the registry or the manager of the current domain and prints its claims.
+The token is minted for the SPIFFE ID of the server, so nsmctl connects to the service to learn it.
+Use --raw to print only the signed token, e.g. 'nsmctl auth token --service manager --raw'.
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var service, err = cmd.Flags().GetString("service")
+			if err != nil {
+				return err
+			}
+			var raw bool
+			if raw, err = cmd.Flags().GetBool("raw"); err != nil {
+				return err
+			}
+
+			var c *client.Client
+			if c, err = clients(cmd.Context()); err != nil {
+				return err
+			}
+
+			var target string
+			switch service {
+			case "registry":
+				target = c.Domain().RegistryService
+			case "manager":
+				target = c.Domain().ManagerService
+			default:
+				return errors.Errorf("unknown service %v, expected registry or manager", service)
+			}
+
+			var token *client.Token
+			if token, err = c.Token(cmd.Context(), target); err != nil {
+				return err
+			}
+
+			if raw {
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), token.Raw)
+				return err
+			}
+
+			var w = tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+			if token.Server == target {
+				printField(w, "SERVER", token.Server)
+			} else {
+				printField(w, "SERVER", fmt.Sprintf("%v (%v)", token.Server, target))
+			}
+			printField(w, "ALG", token.Algorithm)
+			printField(w, "SUB", token.Claims.Subject)
+			printField(w, "AUD", strings.Join(token.Claims.Audience, ", "))
+			if token.Claims.ExpiresAt != nil {
+				printField(w, "EXP", expires(token.Claims.ExpiresAt.Time))
+			}
+			if token.Claims.ExpiresAt == nil || !token.Claims.ExpiresAt.Time.Equal(token.Expires) {
+				printField(w, "USED UNTIL", expires(token.Expires))
+			}
+			printField(w, "TOKEN", token.Raw)
+			return w.Flush()
+		},
+	}
+	r.Flags().String("service", "registry", "service the token is minted for, registry or manager")
+	r.Flags().Bool("raw", false, "print only the signed token")
+	return r
+}
+
+func printField(w io.Writer, name, value string) {
+	_, _ = fmt.Fprintf(w, "%v:\t%v\n", name, value)
+}
+
+// expires formats the expiration time with the time left
+func expires(t time.Time) string {
+	var left = time.Until(t).Round(time.Second)
+	if left < 0 {
+		return t.UTC().Format(time.RFC3339) + " (expired)"
+	}
+	return fmt.Sprintf("%v (in %v)", t.UTC().Format(time.RFC3339), left)
+}
+
+// subject returns the SPIFFE ID of the certificate, the subject name if it has none
+func subject(cert *x509.Certificate) string {
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	return cert.Subject.String()
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package check provides diagnostics of NSM domains
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/nsmctl/pkg/client"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+// Results of the stages
+const (
+	pass = "PASS"
+	fail = "FAIL"
+	skip = "SKIP"
+)
+
+// New creates a new cobra.Command instance that allows to diagnose connectivity to NSM domains.
+// clients returns the client of the domain selected by the context, so the domain is checked with the options
+// of the other commands, e.g. --dial-timeout. The clients are closed by the caller.
+func New(clients func(ctx context.Context) (*client.Client, error)) *cobra.Command {
+	var r = &cobra.Command{
+		Use:               "check",
+		Short:             "checks connectivity to a NSM domain",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `Checks connectivity to the registry and the manager of a NSM domain stage by stage:
+a query to the DNS server, SRV and A/AAAA lookups, TCP connect, SVID fetch, TLS handshake and an API call.
+Prints the result, the time and the details of each stage and hints for the failed ones.
+Checks the current domain if no name passed, e.g. 'nsmctl check domain' or 'nsmctl check domain my-domain'.
+	`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 || args[0] != "domain" && args[0] != "domains" {
+				return errors.New("expected 'check domain [NAME]'")
+			}
+			if len(args) > 2 {
+				return errors.New("only one domain can be checked at a time")
+			}
+
+			var timeout, err = cmd.Flags().GetDuration("timeout")
+			if err != nil {
+				return err
+			}
+
+			var ctx = cmd.Context()
+			if len(args) == 2 {
+				var d *domain.Domain
+				if d, err = domain.Load(args[1]); err != nil {
+					return err
+				}
+				ctx = domain.WithContext(ctx, d)
+			}
+
+			var c *client.Client
+			if c, err = clients(ctx); err != nil {
+				return err
+			}
+
+			var stages = c.Check(ctx, timeout)
+
+			var w = tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', tabwriter.TabIndent)
+			_, _ = fmt.Fprintln(w, "SERVICE\tSTAGE\tRESULT\tTIME\tDETAIL")
+
+			var failed []*client.Stage
+			for _, s := range stages {
+				var result, took, detail = pass, s.Duration.Round(time.Millisecond).String(), s.Detail
+				switch {
+				case s.Skipped:
+					result, took = skip, "-"
+				case s.Err != nil:
+					result, detail = fail, s.Err.Error()
+					failed = append(failed, s)
+				}
+				_, _ = fmt.Fprintln(w, strings.Join([]string{s.Service, s.Name, result, took, detail}, "\t"))
+			}
+			if err = w.Flush(); err != nil {
+				return err
+			}
+
+			if len(failed) == 0 {
+				return nil
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
+			for _, s := range failed {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%v %v: %v\n", s.Service, s.Name, s.Hint())
+			}
+			return errors.Errorf("domain %v failed the check", c.Domain().Name)
+		},
+	}
+	r.Flags().Duration("timeout", 5*time.Second, "timeout of each stage")
+	return r
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config provides control to the configuration of nsmctl: domains and contexts
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/persistence"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+// New creates a new cobra.Command instance that allows to manage the configuration of nsmctl
+func New() *cobra.Command {
+	var r = &cobra.Command{
+		Use:               "config",
+		Short:             "manages the configuration of nsmctl",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `Manages the configuration of nsmctl. A context combines a domain with identity settings and output preferences,
+the domain of the current context is used by default. The configuration is stored in the file set by --config,
+$NSMCTL_CONFIG or nsmctl/config.yaml in the user configuration directory.
+	`,
+	}
+
+	r.AddCommand(
+		newView(),
+		newGetContexts(),
+		newUseContext(),
+		newSetContext(),
+		newDeleteContext(),
+		newRenameContext(),
+	)
+
+	return r
+}
+
+func newView() *cobra.Command {
+	return &cobra.Command{
+		Use:               "view",
+		Short:             "prints the configuration",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Args:              cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var b, err = persistence.View()
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(b)
+			return err
+		},
+	}
+}
+
+func newGetContexts() *cobra.Command {
+	return &cobra.Command{
+		Use:               "get-contexts",
+		Short:             "lists the contexts",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Args:              cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var contexts, err = domain.Contexts()
+			if err != nil {
+				return err
+			}
+			var current *domain.Context
+			if current, err = domain.CurrentContext(); err != nil {
+				return err
+			}
+
+			var w = tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+			_, _ = fmt.Fprintln(w, "CURRENT\tNAME\tDOMAIN\tOUTPUT")
+			for _, c := range contexts {
+				var mark string
+				if current != nil && current.Name == c.Name {
+					mark = "*"
+				}
+				_, _ = fmt.Fprintln(w, strings.Join([]string{mark, c.Name, c.Domain, c.Output}, "\t"))
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newUseContext() *cobra.Command {
+	return &cobra.Command{
+		Use:               "use-context NAME",
+		Short:             "makes the context current",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Args:              cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := domain.UseContext(args[0]); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "switched to context "+args[0])
+			return nil
+		},
+	}
+}
+
+func newSetContext() *cobra.Command {
+	var r = &cobra.Command{
+		Use:               "set-context NAME",
+		Short:             "creates or changes the context",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `Creates or changes the context. Only the passed settings are changed, the domain is set by --domain.
+e.g. 'nsmctl config set-context prod --domain cluster-a --trust-domain example.org -o wide'
+	`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var c, err = persistence.Load[*domain.Context](args[0])
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				c = &domain.Context{Name: args[0]}
+			case err != nil:
+				return err
+			}
+
+			for name, field := range contextFlags(c) {
+				if !cmd.Flags().Changed(name) {
+					continue
+				}
+				if *field, err = cmd.Flags().GetString(name); err != nil {
+					return err
+				}
+			}
+
+			if c.Domain == "" {
+				return errors.New("domain of the context is required, use --domain")
+			}
+			if _, err = c.Resolve(); err != nil {
+				return err
+			}
+			if err = domain.StoreContext(c); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "context "+c.Name+" set")
+			return nil
+		},
+	}
+	r.Flags().String("workload-api-socket", "", "address of the SPIFFE workload API, e.g. unix:///run/spire/sockets/agent.sock")
+	r.Flags().String("cert", "", "PEM file with the X509-SVID certificate, used instead of the workload API")
+	r.Flags().String("key", "", "PEM file with the key of the certificate")
+	r.Flags().String("ca", "", "PEM file with the CA bundle of the servers")
+	r.Flags().String("server-id", "", "SPIFFE ID the servers must have, e.g. spiffe://example.org/nsmgr")
+	r.Flags().String("trust-domain", "", "trust domain the servers must belong to, e.g. example.org")
+	r.Flags().StringP("output", "o", "", "default output format of get and describe, e.g. wide or yaml")
+	return r
+}
+
+// contextFlags maps names of the flags to the fields of the context
+func contextFlags(c *domain.Context) map[string]*string {
+	return map[string]*string{
+		"domain":              &c.Domain,
+		"workload-api-socket": &c.WorkloadAPISocket,
+		"cert":                &c.CertFile,
+		"key":                 &c.KeyFile,
+		"ca":                  &c.CAFile,
+		"server-id":           &c.ServerID,
+		"trust-domain":        &c.TrustDomain,
+		"output":              &c.Output,
+	}
+}
+
+func newDeleteContext() *cobra.Command {
+	return &cobra.Command{
+		Use:               "delete-context NAME",
+		Short:             "deletes the context",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Args:              cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := domain.DeleteContext(args[0]); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "deleted context "+args[0])
+			return nil
+		},
+	}
+}
+
+func newRenameContext() *cobra.Command {
+	return &cobra.Command{
+		Use:               "rename-context NAME NEW_NAME",
+		Short:             "renames the context",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Args:              cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := domain.RenameContext(args[0], args[1]); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "context "+args[0]+" renamed to "+args[1])
+			return nil
+		},
+	}
+}
@@ -18,17 +18,27 @@
 package create
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"os"
 	"reflect"
+	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v2"
 
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/codec"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/manifest"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/patch"
 	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
 )
 
+// Results of applying a document
+const (
+	created   = "created"
+	updated   = "updated"
+	unchanged = "unchanged"
+)
+
 // New creates a new instance of cobra.Command that allows to create resources.
 func New(storages map[string]*storage.Storage) *cobra.Command {
 	var r = &cobra.Command{
@@ -37,63 +47,227 @@ func New(storages map[string]*storage.Storage) *cobra.Command {
 		Short:             "Creates a new resource",
 		SilenceUsage:      true,
 		DisableAutoGenTag: true,
-		Long: `creates a new resource based on the passed type and file. 
+		Long: `creates or updates resources based on the passed type and files. 
 Can create an emptry resouces if passed two arguments (type and name).
+Files may contain several documents separated by '---', the 'kind' field of a document selects the type of the resource,
+e.g. 'kind: NetworkService'. The type argument is used for documents without kind.
+Custom resources of the Kubernetes registry (apiVersion networkservicemesh.io/v1) are accepted as well.
+-f accepts files, directories (read recursively), glob patterns and '-' for stdin, and can be repeated.
+Prints the result per resource: created, updated, unchanged or failed.
+Existing resources are replaced and registered again, so the expiration of the endpoints is refreshed. 'unchanged' means that
+the resource differs from the document only in the fields managed by the server, e.g. url and expiration time.
+Use --merge to merge the documents into the existing resources, so partial documents keep the other fields.
 	`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var (
-				err      error
-				filePath string
-			)
-			filePath, err = cmd.Flags().GetString("from-file")
-			if err != nil {
-				return err
-			}
+			return run(cmd, storages, args)
+		},
+	}
+	r.PersistentFlags().StringArrayP("from-file", "f", nil, "file, directory or glob pattern with resources, '-' reads stdin")
+	r.PersistentFlags().Bool("merge", false, "merge the documents into the existing resources by JSON merge patch instead of replacing them")
+
+	if s, ok := storages["domain"]; ok {
+		r.AddCommand(newDomainCommand(storages, s))
+	}
+
+	return r
+}
+
+// run creates or updates resources of the type from the args or the files
+func run(cmd *cobra.Command, storages map[string]*storage.Storage, args []string) error {
+	var (
+		err       error
+		filePaths []string
+		docs      []*manifest.Document
+		s         *storage.Storage
+		n         string
+		merge     bool
+	)
+	filePaths, err = cmd.Flags().GetStringArray("from-file")
+	if err != nil {
+		return err
+	}
+	if merge, err = cmd.Flags().GetBool("merge"); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		var ok bool
+		if s, ok = storages[args[0]]; !ok {
+			return errors.New("unknown type " + args[0])
+		}
+	}
+	if len(args) > 1 {
+		n = args[1]
+	}
+
+	switch {
+	case len(filePaths) > 0:
+		if docs, err = manifest.Read(filePaths, cmd.InOrStdin()); err != nil {
+			return err
+		}
+		if len(docs) == 0 {
+			return errors.New("no resources found in " + strings.Join(filePaths, ", "))
+		}
+	case s != nil:
+		docs = []*manifest.Document{{Source: "<args>"}}
+	default:
+		return errors.New("resource type or -f is required")
+	}
+
+	if n != "" && len(docs) > 1 {
+		return errors.New("name can not be used with several resources")
+	}
+
+	var failed int
+
+	for _, doc := range docs {
+		var ref, result, applyErr = apply(cmd.Context(), storages, s, doc, n, merge)
+		if applyErr != nil {
+			failed++
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ref+" failed: "+applyErr.Error())
+			continue
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), ref+" "+result)
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%v of %v resources failed", failed, len(docs))
+	}
+	return nil
+}
+
+// apply creates or updates the resource of the document. Returns the reference to the resource and the result.
+func apply(ctx context.Context, storages map[string]*storage.Storage, s *storage.Storage, doc *manifest.Document, n string, merge bool) (ref, result string, err error) {
+	ref = doc.Source
+
+	var resource storage.Resource
+	if s, resource, err = doc.Resource(ctx, storages, s); err != nil {
+		return ref, "", err
+	}
 
-			if len(args) > 0 {
-				var t, n string
-				t = args[0]
-				if len(args) > 1 {
-					n = args[1]
-				}
-				var s, ok = storages[t]
-
-				if !ok {
-					return errors.New("unknown type " + t)
-				}
-
-				var result = storages[t].Create(cmd.Context())
-
-				if filePath != "" {
-					var b []byte
-					// #nosec
-					b, err = os.ReadFile(filePath)
-					if err != nil {
-						return err
-					}
-					if err = yaml.Unmarshal(b, result); err != nil {
-						return err
-					}
-				}
-
-				if n == "" {
-					n = getName(result)
-				}
-
-				var err = s.Update(cmd.Context(), n, result)
-				if err != nil {
-					return err
-				}
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "created "+n)
-				return nil
+	if n == "" {
+		n = getName(resource)
+	}
+	if n == "" {
+		return ref, "", errors.New("name is required")
+	}
+	ref = s.Kind + "/" + n
+
+	result = created
+	if s.Get != nil {
+		var existing, getErr = s.Get(ctx, n)
+		switch {
+		case storage.IsNotFound(getErr):
+		case getErr != nil:
+			return ref, "", getErr
+		case merge:
+			var same bool
+			if same, err = contains(existing, resource); err != nil {
+				return ref, "", err
+			}
+			if same {
+				return ref, unchanged, nil
 			}
+			return ref, updated, mergeInto(ctx, s, n, resource)
+		default:
+			// The resource is replaced anyway, so the registration is refreshed even if nothing has changed
+			var same bool
+			if same, err = equal(s, existing, resource); err != nil {
+				return ref, "", err
+			}
+			result = updated
+			if same {
+				result = unchanged
+			}
+		}
+	}
 
-			return errors.New("")
-		},
+	if err = s.Update(ctx, n, resource); err != nil {
+		return ref, "", err
 	}
-	r.Flags().StringP("from-file", "f", "", "represents format of out")
+	return ref, result, nil
+}
 
-	return r
+// mergeInto merges the resource into the existing one
+func mergeInto(ctx context.Context, s *storage.Storage, n string, r storage.Resource) error {
+	if err := s.Check(storage.VerbPatch); err != nil {
+		return err
+	}
+	var b, err = codec.MarshalJSON(r)
+	if err != nil {
+		return err
+	}
+	_, err = s.Patch(ctx, n, patch.Merge, b)
+	return err
+}
+
+// equal returns true if the resources differ only in the fields that are managed by the server
+func equal(s *storage.Storage, actual, expected storage.Resource) (bool, error) {
+	var a, err = clientFields(s, actual)
+	if err != nil {
+		return false, err
+	}
+	var e any
+	if e, err = clientFields(s, expected); err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(a, e), nil
+}
+
+// clientFields returns the generic value of the resource without the fields that are managed by the server
+func clientFields(s *storage.Storage, r storage.Resource) (any, error) {
+	var v, err = codec.ToValue(r)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := v.(map[string]any); ok {
+		for _, field := range s.ServerFields {
+			delete(m, field)
+		}
+	}
+	return v, nil
+}
+
+// contains returns true if the actual resource has all the fields of the expected one
+func contains(actual, expected storage.Resource) (bool, error) {
+	var a, err = codec.ToValue(actual)
+	if err != nil {
+		return false, err
+	}
+	var e any
+	if e, err = codec.ToValue(expected); err != nil {
+		return false, err
+	}
+	return containsValue(a, e), nil
+}
+
+func containsValue(actual, expected any) bool {
+	switch e := expected.(type) {
+	case map[string]any:
+		var a, ok = actual.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, v := range e {
+			if !containsValue(a[k], v) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		var a, ok = actual.([]any)
+		if !ok || len(a) != len(e) {
+			return false
+		}
+		for i := range e {
+			if !containsValue(a[i], e[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(actual, expected)
+	}
 }
 
 func getName(r storage.Resource) string {
@@ -0,0 +1,144 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package create
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+// newDomainCommand creates a command that creates a domain from the flags
+func newDomainCommand(storages map[string]*storage.Storage, s *storage.Storage) *cobra.Command {
+	var r = &cobra.Command{
+		Use:               "domain NAME",
+		Aliases:           s.ShortNames,
+		Short:             "Creates a new NSM domain",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `creates a new NSM domain. The registry and the manager are 'host:port' addresses or service names
+that are resolved by DNS SRV lookup in the domain, e.g. registry.nsm-system.
+Defaults to the registry.nsm-system registry and the nsmgr-proxy.nsm-system manager.
+Servers are authorized by --server-id or --trust-domain, any server is accepted if none is set.
+The identity of nsmctl comes from the SPIFFE workload API, --cert, --key and --ca use static files instead.
+The first domain becomes the default one, use --set-default to make the new domain default later.
+	`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var filePaths, err = cmd.Flags().GetStringArray("from-file")
+			if err != nil {
+				return err
+			}
+			if len(filePaths) > 0 {
+				if domainFlagsChanged(cmd) {
+					return errors.New("domain flags can not be used together with -f")
+				}
+				return run(cmd, storages, append([]string{s.Kind}, args...))
+			}
+
+			if len(args) != 1 {
+				return errors.New("name of the domain is required")
+			}
+
+			var d = domain.New(args[0])
+			for name, field := range stringFlags(d) {
+				if !cmd.Flags().Changed(name) {
+					continue
+				}
+				if *field, err = cmd.Flags().GetString(name); err != nil {
+					return err
+				}
+			}
+			if d.IsInsecure, err = cmd.Flags().GetBool("insecure"); err != nil {
+				return err
+			}
+			if d.IsDefault, err = cmd.Flags().GetBool("set-default"); err != nil {
+				return err
+			}
+			if err = d.Validate(); err != nil {
+				return err
+			}
+
+			if _, err = s.Get(cmd.Context(), d.Name); err == nil {
+				return errors.Errorf("%v %v already exists", s.Kind, d.Name)
+			} else if !storage.IsNotFound(err) {
+				return err
+			}
+
+			var domains []storage.Resource
+			if domains, err = s.List(cmd.Context()); err != nil {
+				return err
+			}
+			if len(domains) == 0 {
+				d.IsDefault = true
+			}
+			if err = s.Update(cmd.Context(), d.Name, d); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), s.Kind+"/"+d.Name+" "+created)
+			return nil
+		},
+	}
+	r.Flags().String("dns-server", "", "address of the DNS server that resolves services of the domain, e.g. 10.0.0.10:53. The system resolver is used if not set")
+	r.Flags().String("registry", "", "registry service, 'host:port' or a service name, default registry.nsm-system")
+	r.Flags().String("manager", "", "manager service, 'host:port' or a service name, default nsmgr-proxy.nsm-system")
+	r.Flags().Bool("insecure", false, "connect to the domain without TLS")
+	r.Flags().String("workload-api-socket", "", "address of the SPIFFE workload API, e.g. unix:///run/spire/sockets/agent.sock. Defaults to $SPIFFE_ENDPOINT_SOCKET or unix:///tmp/spire-agent/public/api.sock")
+	r.Flags().String("cert", "", "PEM file with the X509-SVID certificate, used instead of the workload API")
+	r.Flags().String("key", "", "PEM file with the key of the certificate")
+	r.Flags().String("ca", "", "PEM file with the CA bundle of the servers, required with --cert")
+	r.Flags().String("server-id", "", "SPIFFE ID the servers must have, e.g. spiffe://example.org/nsmgr")
+	r.Flags().String("trust-domain", "", "trust domain the servers must belong to, e.g. example.org")
+	r.Flags().String("token-lifetime", "", "lifetime of JWT tokens sent with requests, e.g. 30m, default 1h")
+	r.Flags().String("tls-min-version", "", "minimum TLS version, 1.2 or 1.3, default 1.2")
+	r.Flags().Bool("set-default", false, "make the domain default")
+	return r
+}
+
+// stringFlags maps names of the string flags to the fields of the domain
+func stringFlags(d *domain.Domain) map[string]*string {
+	return map[string]*string{
+		"dns-server":          &d.DNSServerAddress,
+		"registry":            &d.RegistryService,
+		"manager":             &d.ManagerService,
+		"workload-api-socket": &d.WorkloadAPISocket,
+		"cert":                &d.CertFile,
+		"key":                 &d.KeyFile,
+		"ca":                  &d.CAFile,
+		"server-id":           &d.ServerID,
+		"trust-domain":        &d.TrustDomain,
+		"token-lifetime":      &d.TokenLifetime,
+		"tls-min-version":     &d.TLSMinVersion,
+	}
+}
+
+// domainFlagsChanged returns true if any flag of the domain is set on the command line
+func domainFlagsChanged(cmd *cobra.Command) bool {
+	var names = []string{"insecure", "set-default"}
+	for name := range stringFlags(new(domain.Domain)) {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
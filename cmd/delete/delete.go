@@ -23,22 +23,23 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/reader"
 	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
 )
 
 // New creates a new  *cobra.Command that allows to delete NSM resources
 func New(storages map[string]*storage.Storage) *cobra.Command {
-	return &cobra.Command{
+	var r = &cobra.Command{
 		Use:               "delete",
 		Short:             "Deletes a NSM resource",
 		SilenceUsage:      true,
 		DisableAutoGenTag: true,
 		Long: `Deletes nsm resouces that may delete the user. 
 Delete can not delete resouces created by the another user beasd on the default OPA NSM policies.
-Expects type of the resource that need to delete and list of names. 
+Expects type of the resource that need to delete and list of names or a label selector. 
 	`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) < 2 {
+			if len(args) == 0 {
 				return errors.New("resource type is required")
 			}
 
@@ -50,8 +51,35 @@ Expects type of the resource that need to delete and list of names.
 				return errors.New("unknown type " + resourceType)
 			}
 
-			for _, item := range args[1:] {
-				if err := s.Delete(cmd.Context(), item); err != nil {
+			if err := s.Check(storage.VerbDelete); err != nil {
+				return err
+			}
+
+			var names = args[1:]
+
+			var q, err = reader.Query(cmd)
+			if err != nil {
+				return err
+			}
+			if err = reader.CheckQuery(q, resourceType, s, names); err != nil {
+				return err
+			}
+
+			switch {
+			case q.Empty() && len(names) == 0:
+				return errors.New("names or a label selector are required")
+			case !q.Empty():
+				var list, listErr = s.SelectQuery(cmd.Context(), q)
+				if listErr != nil {
+					return listErr
+				}
+				for _, item := range list {
+					names = append(names, storage.NameOf(item))
+				}
+			}
+
+			for _, item := range names {
+				if err = s.Delete(cmd.Context(), item); err != nil {
 					return err
 				}
 				fmt.Println("removed " + resourceType + " " + item)
@@ -59,4 +87,6 @@ Expects type of the resource that need to delete and list of names.
 			return nil
 		},
 	}
+	reader.AddQueryFlags(r)
+	return r
 }
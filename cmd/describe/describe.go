@@ -19,29 +19,19 @@ package describe
 
 import (
 	"errors"
-	"io"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v2"
 
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/listing"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/printer"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/reader"
 	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
 )
 
 // Printer prints resources
 type Printer interface {
-	Print([]any)
-}
-
-type yamlPrinter struct {
-	out io.Writer
-}
-
-func (d *yamlPrinter) Print(itens []any) {
-	for _, item := range itens {
-		var b, _ = yaml.Marshal(item)
-		_, _ = d.out.Write(b)
-		_, _ = d.out.Write([]byte("\n"))
-	}
+	Print([]any) error
 }
 
 // New creates a new instance of cobra.Command that allows to describe resources
@@ -54,11 +44,24 @@ func New(storages map[string]*storage.Storage) *cobra.Command {
 		DisableAutoGenTag: true,
 		Long: `Describes NSM resources from the current NSM Domain. 
 If no name passed describes list of the resources instead.
+Interdomain names 'name@domain' are read from the registry of the stored domain with the name,
+names of other domains are resolved by the registry of the current domain.
+--all-domains and --domains query several domains concurrently, lists of the domains are merged into one list.
+Lists support --sort-by, --field-selector, --limit and --offset like 'nsmctl get'.
 	`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var p Printer = &yamlPrinter{out: cmd.OutOrStdout()}
-
-			var items []interface{}
+			var output, err = cmd.Flags().GetString("output")
+			if err != nil {
+				return err
+			}
+			// Resources are described in detail, so the table of the context is not used
+			var contextOutput string
+			if contextOutput, err = reader.Output(cmd, output); err != nil {
+				return err
+			}
+			if contextOutput != "wide" {
+				output = contextOutput
+			}
 
 			if len(args) == 0 {
 				return errors.New("resource type is required")
@@ -72,28 +75,46 @@ If no name passed describes list of the resources instead.
 				return errors.New("unknown type " + resourceType)
 			}
 
-			if len(args) == 1 {
-				var list, _ = s.List(cmd.Context())
+			var q *storage.Query
+			var opts *listing.Options
+			if q, opts, err = reader.Selection(cmd, resourceType, s, args[1:]); err != nil {
+				return err
+			}
 
-				for _, item := range list {
-					items = append(items, item)
-				}
+			var domains []*domain.Domain
+			if domains, err = reader.Domains(cmd); err != nil {
+				return err
 			}
 
-			if len(args) > 1 {
-				for _, item := range args[1:] {
-					v, getErr := s.Get(cmd.Context(), item)
-					if getErr != nil {
-						return getErr
-					}
-					items = append(items, v)
-				}
+			// A resource requested by the name from one domain is printed on its own
+			var p Printer
+			if p, err = printer.New(output, cmd.OutOrStdout(), len(args) == 2 && len(domains) == 0); err != nil {
+				return err
 			}
 
-			p.Print(items)
+			var list []storage.Resource
+			if len(domains) == 0 {
+				list, err = reader.Fetch(cmd.Context(), s, args[1:], q, opts)
+			} else {
+				if !s.PerDomain {
+					return errors.New("--all-domains and --domains can not be used with " + resourceType + ", it is not a resource of a domain")
+				}
+				list, _, err = reader.FromDomains(cmd.Context(), cmd.ErrOrStderr(), domains, s, args[1:], q, opts)
+			}
+			if err != nil {
+				return err
+			}
 
-			return nil
+			var items []any
+			for _, item := range list {
+				items = append(items, item)
+			}
+			return p.Print(items)
 		},
 	}
+	r.Flags().StringP("output", "o", "yaml", "output format: "+printer.Formats)
+	reader.AddQueryFlags(r)
+	reader.AddListFlags(r)
+	reader.AddDomainFlags(r)
 	return r
 }
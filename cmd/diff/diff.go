@@ -0,0 +1,176 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diff provides control to compare manifests with the resources of the domain
+package diff
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/codec"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/manifest"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+)
+
+// ErrDrift means that the resources of the domain differ from the manifests
+var ErrDrift = errors.New("resources differ from the manifests")
+
+// New creates a new instance of cobra.Command that allows to compare manifests with the resources
+func New(storages map[string]*storage.Storage) *cobra.Command {
+	var r = &cobra.Command{
+		Use:               "diff",
+		Short:             "Shows what apply would change",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `Compares resources from the files with the resources of the current NSM Domain and prints the unified diff.
+Accepts the same files as apply. The type argument is used for documents without kind.
+Exits with 0 if there are no differences, 1 if there are differences and another non-zero code on errors.
+	`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				err                error
+				filePaths          []string
+				ignoreServerFields bool
+				docs               []*manifest.Document
+				s                  *storage.Storage
+			)
+			if filePaths, err = cmd.Flags().GetStringArray("from-file"); err != nil {
+				return err
+			}
+			if ignoreServerFields, err = cmd.Flags().GetBool("ignore-server-fields"); err != nil {
+				return err
+			}
+
+			if len(filePaths) == 0 {
+				return errors.New("-f is required")
+			}
+
+			if len(args) > 0 {
+				var ok bool
+				if s, ok = storages[args[0]]; !ok {
+					return errors.New("unknown type " + args[0])
+				}
+			}
+
+			if docs, err = manifest.Read(filePaths, cmd.InOrStdin()); err != nil {
+				return err
+			}
+
+			var failed, changed int
+
+			for _, doc := range docs {
+				var text, diffErr = diff(cmd.Context(), storages, s, doc, ignoreServerFields)
+				if diffErr != nil {
+					failed++
+					cmd.PrintErrln(doc.Source + " failed: " + diffErr.Error())
+					continue
+				}
+				if text != "" {
+					changed++
+					cmd.Print(text)
+				}
+			}
+
+			if failed > 0 {
+				return errors.Errorf("%v of %v resources failed", failed, len(docs))
+			}
+			if changed > 0 {
+				cmd.SilenceErrors = true
+				return ErrDrift
+			}
+			return nil
+		},
+	}
+	r.Flags().StringArrayP("from-file", "f", nil, "file, directory or glob pattern with resources, '-' reads stdin")
+	r.Flags().Bool("ignore-server-fields", false, "ignore the fields that are managed by the server, e.g. url and expiration time")
+
+	return r
+}
+
+// diff returns the unified diff between the resource of the domain and the document, empty if there is no difference
+func diff(ctx context.Context, storages map[string]*storage.Storage, s *storage.Storage, doc *manifest.Document, ignoreServerFields bool) (string, error) {
+	var desired storage.Resource
+	var err error
+
+	if s, desired, err = doc.Resource(ctx, storages, s); err != nil {
+		return "", err
+	}
+	if err = s.Check(storage.VerbGet); err != nil {
+		return "", err
+	}
+
+	var name = storage.NameOf(desired)
+	if name == "" {
+		return "", errors.New("name is required")
+	}
+	var ref = s.Kind + "/" + name
+
+	var a, b string
+
+	live, err := s.Get(ctx, name)
+	switch {
+	case storage.IsNotFound(err):
+	case err != nil:
+		return "", err
+	default:
+		if a, err = toYAML(s, live, ignoreServerFields); err != nil {
+			return "", err
+		}
+	}
+
+	if b, err = toYAML(s, desired, ignoreServerFields); err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(a),
+		B:        splitLines(b),
+		FromFile: "live/" + ref,
+		ToFile:   "manifest/" + ref,
+		Context:  3,
+	})
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return difflib.SplitLines(strings.TrimSuffix(s, "\n"))
+}
+
+func toYAML(s *storage.Storage, r storage.Resource, ignoreServerFields bool) (string, error) {
+	var v, err = codec.ToValue(r)
+	if err != nil {
+		return "", err
+	}
+
+	if m, ok := v.(map[string]any); ok && ignoreServerFields {
+		for _, field := range s.ServerFields {
+			delete(m, field)
+		}
+	}
+
+	var b []byte
+	if b, err = codec.MarshalYAML(v); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
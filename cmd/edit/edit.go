@@ -0,0 +1,199 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package edit provides control to edit resources
+package edit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/codec"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+)
+
+const defaultEditor = "vi"
+
+const header = `# Please edit the resource below. Lines beginning with a '#' will be ignored,
+# and an empty file will abort the edit. If an error occurs while saving this file will be
+# reopened with the relevant failures.
+#
+`
+
+// New creates a new instance of cobra.Command that allows to edit resources
+func New(storages map[string]*storage.Storage) *cobra.Command {
+	var r = &cobra.Command{
+		Use:               "edit",
+		Short:             "Edits a NSM resource",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `Opens the resource in the editor and stores the result. 
+The editor is taken from NSMCTL_EDITOR or EDITOR environment variables, vi is used by default.
+Expects type and name of the resource.
+	`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("resource type and name are required")
+			}
+
+			var resourceType, name = args[0], args[1]
+
+			var s, ok = storages[resourceType]
+
+			if !ok {
+				return errors.New("unknown type " + resourceType)
+			}
+
+			if err := s.Check(storage.VerbGet, storage.VerbCreate); err != nil {
+				return err
+			}
+
+			var resource, err = s.Get(cmd.Context(), name)
+			if err != nil {
+				return err
+			}
+
+			var original []byte
+			if original, err = codec.MarshalYAML(resource); err != nil {
+				return err
+			}
+
+			var edited = original
+			var editErr error
+
+			for {
+				var b []byte
+				if b, err = runEditor(cmd, withHeader(edited, editErr)); err != nil {
+					return err
+				}
+				b = stripComments(b)
+
+				if len(bytes.TrimSpace(b)) == 0 || bytes.Equal(b, stripComments(original)) {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "edit cancelled, no changes made")
+					return nil
+				}
+				if editErr != nil && bytes.Equal(b, stripComments(edited)) {
+					return editErr
+				}
+
+				edited = b
+				if resource, editErr = parse(cmd.Context(), s, name, b); editErr != nil {
+					continue
+				}
+				if editErr = s.Update(cmd.Context(), name, resource); editErr != nil {
+					continue
+				}
+
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), s.Kind+"/"+name+" edited")
+				return nil
+			}
+		},
+	}
+
+	return r
+}
+
+// parse validates the edited document
+func parse(ctx context.Context, s *storage.Storage, name string, b []byte) (storage.Resource, error) {
+	var result = s.Create(ctx)
+
+	if err := codec.UnmarshalYAML(b, result); err != nil {
+		return nil, err
+	}
+
+	if n := storage.NameOf(result); n != name {
+		return nil, errors.Errorf("name can not be changed from %v to %v", name, n)
+	}
+
+	return result, nil
+}
+
+func withHeader(b []byte, err error) []byte {
+	var result bytes.Buffer
+
+	result.WriteString(header)
+	if err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			result.WriteString("# error: " + line + "\n")
+		}
+		result.WriteString("#\n")
+	}
+	result.Write(b)
+
+	return result.Bytes()
+}
+
+func stripComments(b []byte) []byte {
+	var result bytes.Buffer
+	var scanner = bufio.NewScanner(bytes.NewReader(b))
+
+	for scanner.Scan() {
+		if strings.HasPrefix(strings.TrimSpace(scanner.Text()), "#") {
+			continue
+		}
+		result.Write(scanner.Bytes())
+		result.WriteByte('\n')
+	}
+
+	return result.Bytes()
+}
+
+// runEditor opens the content in the editor and returns the saved content
+func runEditor(cmd *cobra.Command, content []byte) ([]byte, error) {
+	f, err := os.CreateTemp("", "nsmctl-edit-*.yaml")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if _, err = f.Write(content); err != nil {
+		_ = f.Close()
+		return nil, errors.WithStack(err)
+	}
+	if err = f.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var editor = os.Getenv("NSMCTL_EDITOR")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = defaultEditor
+	}
+
+	// editors with arguments, e.g. 'code --wait', are started by the shell
+	// #nosec
+	var c = exec.CommandContext(cmd.Context(), "sh", "-c", editor+` "$0"`, f.Name())
+	c.Stdin = cmd.InOrStdin()
+	c.Stdout = cmd.OutOrStdout()
+	c.Stderr = cmd.ErrOrStderr()
+
+	if err = c.Run(); err != nil {
+		return nil, errors.Wrapf(err, "editor %v failed", editor)
+	}
+
+	// #nosec
+	return os.ReadFile(f.Name())
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package explain provides control to document fields of resources
+package explain
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/explain"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+)
+
+// New creates a new instance of cobra.Command that documents fields of resources
+func New(storages map[string]*storage.Storage) *cobra.Command {
+	var r = &cobra.Command{
+		Use:               "explain",
+		Short:             "Documents fields of a resource type",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `Prints fields of the resource type and their types as they are written in manifests.
+Expects the type and optionally the path of the field, e.g. 'nsmctl explain nse.networkServiceLabels'.
+	`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("resource type is required")
+			}
+
+			var recursive, err = cmd.Flags().GetBool("recursive")
+			if err != nil {
+				return err
+			}
+
+			var path = strings.Split(args[0], ".")
+			var resourceType = path[0]
+
+			var s, ok = storages[resourceType]
+
+			if !ok {
+				return errors.New("unknown type " + resourceType)
+			}
+
+			var f *explain.Field
+			if f, err = explain.Describe(s.Type); err != nil {
+				return err
+			}
+			if f, err = f.Lookup(path[1:]...); err != nil {
+				return err
+			}
+
+			return explain.Print(cmd.OutOrStdout(), s.Kind, path[1:], f, recursive)
+		},
+	}
+	r.Flags().Bool("recursive", false, "print the nested fields as well")
+
+	return r
+}
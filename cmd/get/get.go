@@ -29,67 +29,146 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/listing"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/printer"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/reader"
 	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
 )
 
 const maxTabPrinterLen = 15
 
 // Printer prints resources
 type Printer interface {
-	Print([]any)
+	Print([]any) error
 }
 
+// tabPrinter prints resources as a table. Columns declared by the storage are used if present,
+// otherwise the table contains all exported fields of the resource.
 type tabPrinter struct {
-	out io.Writer
+	out     io.Writer
+	columns []storage.Column
+	wide    bool
 }
 
-func (p *tabPrinter) Print(list []any) {
+func (p *tabPrinter) Print(list []any) error {
 	w := tabwriter.NewWriter(p.out, 0, 0, 3, ' ', tabwriter.TabIndent)
 
 	for j, item := range list {
-		var outStr strings.Builder
-		v := reflect.ValueOf(item)
+		if j == 0 {
+			fmt.Fprintln(w, strings.Join(p.header(item), "\t"))
+		}
+
+		fmt.Fprintln(w, strings.Join(p.row(item), "\t"))
+	}
+	return w.Flush()
+}
+
+func (p *tabPrinter) header(item any) []string {
+	if len(p.columns) == 0 {
+		return header(indirect(item))
+	}
+
+	var result []string
 
-		if v.Kind() == reflect.Ptr {
-			v = v.Elem()
+	for _, c := range p.columns {
+		if c.Wide && !p.wide {
+			continue
 		}
+		result = append(result, c.Name)
+	}
 
-		if j == 0 {
-			for i := 0; i < v.NumField(); i++ {
-				if !v.Type().Field(i).IsExported() {
-					continue
-				}
+	return result
+}
 
-				var name = toSnakeCase(fmt.Sprint(v.Type().Field(i).Name))
-				_, _ = outStr.WriteString(strings.ToUpper(name))
+func (p *tabPrinter) row(item any) []string {
+	if len(p.columns) == 0 {
+		return row(indirect(item))
+	}
 
-				if i+1 < v.NumField() {
-					outStr.Write([]byte("\t"))
-				}
-			}
-			fmt.Fprintln(w, outStr.String())
-			outStr.Reset()
+	var result []string
+
+	for _, c := range p.columns {
+		if c.Wide && !p.wide {
+			continue
+		}
+		var value = c.Value(item.(storage.Resource))
+		if value == "" {
+			value = "<none>"
 		}
+		result = append(result, value)
+	}
 
-		for i := 0; i < v.NumField(); i++ {
-			if !v.Type().Field(i).IsExported() {
-				continue
-			}
+	return result
+}
 
-			var value = fmt.Sprint(v.Field(i).Interface())
-			if isComplex(v.Field(i).Kind()) {
-				if len(value) > maxTabPrinterLen {
-					value = value[:maxTabPrinterLen-3] + "..."
-				}
+// eventPrinter prints watch events as they happen, the first column contains the type of the event.
+// The events share one writer, its minimum cell width fits the header so the rows stay aligned between flushes.
+type eventPrinter struct {
+	table *tabPrinter
+	w     *tabwriter.Writer
+}
+
+func (p *eventPrinter) PrintEvent(e *storage.Event) error {
+	if p.w == nil {
+		var header = append([]string{"EVENT"}, p.table.header(e.Resource)...)
+		var width = maxTabPrinterLen
+		for _, name := range header {
+			if len(name) > width {
+				width = len(name)
 			}
-			outStr.WriteString(value)
-			if i+1 < v.NumField() {
-				outStr.Write([]byte("\t"))
+		}
+		p.w = tabwriter.NewWriter(p.table.out, width, 0, 3, ' ', tabwriter.TabIndent)
+		fmt.Fprintln(p.w, strings.Join(header, "\t"))
+	}
+
+	fmt.Fprintln(p.w, strings.Join(append([]string{string(e.Type)}, p.table.row(e.Resource)...), "\t"))
+	return p.w.Flush()
+}
+
+func indirect(item any) reflect.Value {
+	v := reflect.ValueOf(item)
+
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	return v
+}
+
+func header(v reflect.Value) []string {
+	var result []string
+
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+
+		var name = toSnakeCase(fmt.Sprint(v.Type().Field(i).Name))
+		result = append(result, strings.ToUpper(name))
+	}
+
+	return result
+}
+
+func row(v reflect.Value) []string {
+	var result []string
+
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+
+		var value = fmt.Sprint(v.Field(i).Interface())
+		if isComplex(v.Field(i).Kind()) {
+			if len(value) > maxTabPrinterLen {
+				value = value[:maxTabPrinterLen-3] + "..."
 			}
 		}
-		fmt.Fprintln(w, outStr.String())
+		result = append(result, value)
 	}
-	_ = w.Flush()
+
+	return result
 }
 
 func isComplex(k reflect.Kind) bool {
@@ -110,21 +189,16 @@ func New(storages map[string]*storage.Storage) *cobra.Command {
 		DisableAutoGenTag: true,
 		Long: `Gets NSM resources from the current NSM Domain. 
 If no name passed gets list of the resources instead.
+Interdomain names 'name@domain' are read from the registry of the stored domain with the name,
+names of other domains are resolved by the registry of the current domain.
+--all-domains and --domains query several domains concurrently, errors of a domain are reported without failing the others,
+the table gets a DOMAIN column. Lists of the domains are merged, then sorted, selected and paged as one list.
+Lists are sorted by name, use --sort-by to sort by another field, e.g. --sort-by=.expirationTime.
+--field-selector selects resources by the values of their fields, e.g. --field-selector=networkServiceNames=foo,url!=
+--limit and --offset page through long lists.
+Use --watch to keep printing added, modified and deleted resources as they happen.
 	`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var goTemplate string
-			var err error
-			var templ *template.Template
-
-			goTemplate, err = cmd.Flags().GetString("go-template")
-			if err != nil {
-				return err
-			}
-
-			var p Printer = &tabPrinter{out: cmd.OutOrStdout()}
-
-			var items []interface{}
-
 			if len(args) == 0 {
 				return errors.New("resource type is required")
 			}
@@ -137,46 +211,185 @@ If no name passed gets list of the resources instead.
 				return errors.New("unknown type " + resourceType)
 			}
 
-			if len(args) == 1 {
-				var list, _ = s.List(cmd.Context())
+			var o, err = parseOptions(cmd, resourceType, s, args[1:])
+			if err != nil {
+				return err
+			}
 
-				for _, item := range list {
-					items = append(items, item)
-				}
+			if o.watch {
+				return watchResources(cmd, s, args[1:], o.query, o.list.FieldSelector, o.templ, o.printer)
 			}
 
-			if len(args) > 1 {
-				for _, item := range args[1:] {
-					v, getErr := s.Get(cmd.Context(), item)
-					if getErr != nil {
-						return getErr
-					}
-					items = append(items, v)
-				}
+			if len(o.domains) > 0 {
+				return getFromDomains(cmd, o.domains, s, args[1:], o.query, o.list, o.templ, o.printer)
 			}
 
-			if goTemplate != "" {
-				templ, err = template.New("get/gotemplate").Parse(goTemplate)
-				if err != nil {
-					return err
-				}
+			var list, fetchErr = reader.Fetch(cmd.Context(), s, args[1:], o.query, o.list)
+			if fetchErr != nil {
+				return fetchErr
+			}
+
+			var items []interface{}
+			for _, item := range list {
+				items = append(items, item)
+			}
+
+			if o.templ != nil {
 				var templArgs interface{} = items
 
 				if len(args) == 2 {
 					templArgs = items[0]
 				}
-				return templ.Execute(cmd.OutOrStdout(), templArgs)
+				return o.templ.Execute(cmd.OutOrStdout(), templArgs)
 			}
 
-			p.Print(items)
-
-			return nil
+			return o.printer.Print(items)
 		},
 	}
 	r.Flags().StringP("go-template", "", "", "epects 'go-tempalte' ")
+	r.Flags().StringP("output", "o", "", "output format: wide|"+printer.Formats+". Prints a table if not set")
+	r.Flags().BoolP("watch", "w", false, "after listing/getting the requested resources, watch for changes")
+	reader.AddQueryFlags(r)
+	reader.AddListFlags(r)
+	reader.AddDomainFlags(r)
 	return r
 }
 
+// options are the parsed flags of get
+type options struct {
+	templ   *template.Template
+	query   *storage.Query
+	list    *listing.Options
+	domains []*domain.Domain
+	watch   bool
+	printer Printer
+}
+
+// parseOptions parses the flags of get for the resources of the storage requested by the names
+func parseOptions(cmd *cobra.Command, resourceType string, s *storage.Storage, names []string) (*options, error) {
+	var o = new(options)
+
+	var goTemplate, err = cmd.Flags().GetString("go-template")
+	if err != nil {
+		return nil, err
+	}
+	if goTemplate != "" {
+		if o.templ, err = template.New("get/gotemplate").Parse(goTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	var output string
+	if output, err = cmd.Flags().GetString("output"); err != nil {
+		return nil, err
+	}
+	if output, err = reader.Output(cmd, output); err != nil {
+		return nil, err
+	}
+
+	if o.watch, err = cmd.Flags().GetBool("watch"); err != nil {
+		return nil, err
+	}
+	if o.query, o.list, err = reader.Selection(cmd, resourceType, s, names); err != nil {
+		return nil, err
+	}
+	if o.domains, err = reader.Domains(cmd); err != nil {
+		return nil, err
+	}
+	if err = o.check(resourceType, s); err != nil {
+		return nil, err
+	}
+
+	// A resource requested by the name from one domain and events of a watch are printed on their own
+	if o.printer, err = newPrinter(cmd, s, output, len(names) == 1 && len(o.domains) == 0 || o.watch); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// check checks that the options can be used together
+func (o *options) check(resourceType string, s *storage.Storage) error {
+	if o.watch && (o.list.SortBy != "" || o.list.Limit != 0 || o.list.Offset != 0) {
+		return errors.New("--sort-by, --limit and --offset can not be used together with --watch")
+	}
+	if o.watch && len(o.domains) > 0 {
+		return errors.New("--all-domains and --domains can not be used together with --watch")
+	}
+	if len(o.domains) > 0 && !s.PerDomain {
+		return errors.New("--all-domains and --domains can not be used with " + resourceType + ", it is not a resource of a domain")
+	}
+	return nil
+}
+
+// newPrinter returns the printer of the output format, a table if the format is empty or wide
+func newPrinter(cmd *cobra.Command, s *storage.Storage, output string, single bool) (Printer, error) {
+	if output == "" || output == "wide" {
+		return &tabPrinter{out: cmd.OutOrStdout(), columns: s.Columns, wide: output == "wide"}, nil
+	}
+	return printer.New(output, cmd.OutOrStdout(), single)
+}
+
+// getFromDomains prints the resources of the domains, the table gets a DOMAIN column
+func getFromDomains(cmd *cobra.Command, domains []*domain.Domain, s *storage.Storage, names []string, q *storage.Query, opts *listing.Options, templ *template.Template, p Printer) error {
+	var list, domainOf, err = reader.FromDomains(cmd.Context(), cmd.ErrOrStderr(), domains, s, names, q, opts)
+	if err != nil {
+		return err
+	}
+
+	var items []any
+	for _, item := range list {
+		items = append(items, item)
+	}
+
+	if templ != nil {
+		return templ.Execute(cmd.OutOrStdout(), items)
+	}
+
+	if table, ok := p.(*tabPrinter); ok {
+		var domainColumn = storage.Column{Name: "DOMAIN", Value: func(r storage.Resource) string { return domainOf[r] }}
+		p = &tabPrinter{out: table.out, columns: append([]storage.Column{domainColumn}, table.columns...), wide: table.wide}
+	}
+
+	return p.Print(items)
+}
+
+func watchResources(cmd *cobra.Command, s *storage.Storage, names []string, q *storage.Query, fieldSelector string, templ *template.Template, p Printer) error {
+	if err := s.Check(storage.VerbWatch); err != nil {
+		return err
+	}
+
+	var fields, err = listing.ParseFieldSelector(fieldSelector)
+	if err != nil {
+		return err
+	}
+
+	var filter = make(map[string]struct{})
+	for _, name := range names {
+		filter[name] = struct{}{}
+	}
+
+	var ep *eventPrinter
+	if table, ok := p.(*tabPrinter); ok {
+		ep = &eventPrinter{table: table}
+	}
+
+	return s.Watch(cmd.Context(), func(e *storage.Event) error {
+		if _, ok := filter[storage.NameOf(e.Resource)]; len(filter) > 0 && !ok {
+			return nil
+		}
+		if !s.Matches(e.Resource, q) || !fields.Matches(e.Resource) {
+			return nil
+		}
+		if templ != nil {
+			return templ.Execute(cmd.OutOrStdout(), e)
+		}
+		if ep == nil {
+			return p.Print([]any{e.Resource})
+		}
+		return ep.PrintEvent(e)
+	})
+}
+
 func toSnakeCase(str string) string {
 	var matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
 	var matchAllCap = regexp.MustCompile("([a-z0-9])([A-Z])")
@@ -0,0 +1,79 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsmctl
+
+import (
+	"context"
+	"sync"
+
+	"github.com/networkservicemesh/nsmctl/pkg/client"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+// clientManager keeps a client per NSM domain for a single nsmctl invocation, Close releases all of them
+type clientManager struct {
+	mu      sync.Mutex
+	options []client.Option
+	clients map[string]*client.Client
+}
+
+func newClientManager() *clientManager {
+	return &clientManager{
+		clients: make(map[string]*client.Client),
+	}
+}
+
+// configure sets options of the clients that are created after the call
+func (m *clientManager) configure(opts ...client.Option) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.options = opts
+}
+
+// current returns the client of the domain selected by the context or the current domain
+func (m *clientManager) current(ctx context.Context) (*client.Client, error) {
+	var d, err = domain.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var c, ok = m.clients[d.Name]
+	if !ok {
+		c = client.New(d, m.options...)
+		m.clients[d.Name] = c
+	}
+	return c, nil
+}
+
+// Close closes clients of all the domains
+func (m *clientManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result error
+	for name, c := range m.clients {
+		if err := c.Close(); err != nil && result == nil {
+			result = err
+		}
+		delete(m.clients, name)
+	}
+	return result
+}
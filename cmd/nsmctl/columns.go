@@ -0,0 +1,155 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsmctl
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+func withColumns(s *storage.Storage, columns ...storage.Column) *storage.Storage {
+	s.Columns = columns
+	return s
+}
+
+func domainColumns() []storage.Column {
+	return []storage.Column{
+		{Name: "NAME", Value: func(r storage.Resource) string { return r.(*domain.Domain).Name }},
+		{Name: "REGISTRY", Value: func(r storage.Resource) string { return r.(*domain.Domain).RegistryService }},
+		{Name: "MANAGER", Value: func(r storage.Resource) string { return r.(*domain.Domain).ManagerService }},
+		{Name: "DEFAULT", Value: func(r storage.Resource) string { return strconv.FormatBool(r.(*domain.Domain).IsDefault) }},
+		{Name: "DNS-SERVER", Wide: true, Value: func(r storage.Resource) string { return r.(*domain.Domain).DNSServerAddress }},
+		{Name: "INSECURE", Wide: true, Value: func(r storage.Resource) string { return strconv.FormatBool(r.(*domain.Domain).IsInsecure) }},
+	}
+}
+
+func nsColumns() []storage.Column {
+	return []storage.Column{
+		{Name: "NAME", Value: func(r storage.Resource) string { return r.(*registry.NetworkService).GetName() }},
+		{Name: "PAYLOAD", Value: func(r storage.Resource) string { return r.(*registry.NetworkService).GetPayload() }},
+		{Name: "MATCHES", Value: func(r storage.Resource) string { return strconv.Itoa(len(r.(*registry.NetworkService).GetMatches())) }},
+		{Name: "ROUTES", Wide: true, Value: func(r storage.Resource) string {
+			var routes int
+			for _, m := range r.(*registry.NetworkService).GetMatches() {
+				routes += len(m.GetRoutes())
+			}
+			return strconv.Itoa(routes)
+		}},
+	}
+}
+
+func nseColumns() []storage.Column {
+	return []storage.Column{
+		{Name: "NAME", Value: func(r storage.Resource) string { return r.(*registry.NetworkServiceEndpoint).GetName() }},
+		{Name: "SERVICES", Value: func(r storage.Resource) string {
+			return strings.Join(r.(*registry.NetworkServiceEndpoint).GetNetworkServiceNames(), ",")
+		}},
+		{Name: "URL", Value: func(r storage.Resource) string { return r.(*registry.NetworkServiceEndpoint).GetUrl() }},
+		{Name: "EXPIRES-IN", Value: func(r storage.Resource) string {
+			return until(r.(*registry.NetworkServiceEndpoint).GetExpirationTime())
+		}},
+		{Name: "LABELS", Wide: true, Value: func(r storage.Resource) string {
+			var nse = r.(*registry.NetworkServiceEndpoint)
+			var services []string
+			for _, service := range nse.GetNetworkServiceNames() {
+				if labels := nse.GetNetworkServiceLabels()[service].GetLabels(); len(labels) > 0 {
+					services = append(services, service+":"+formatLabels(labels))
+				}
+			}
+			return strings.Join(services, " ")
+		}},
+		{Name: "AGE", Wide: true, Value: func(r storage.Resource) string {
+			return since(r.(*registry.NetworkServiceEndpoint).GetInitialRegistrationTime())
+		}},
+	}
+}
+
+func connectionColumns() []storage.Column {
+	return []storage.Column{
+		{Name: "ID", Value: func(r storage.Resource) string { return r.(*networkservice.Connection).GetId() }},
+		{Name: "NETWORK-SERVICE", Value: func(r storage.Resource) string { return r.(*networkservice.Connection).GetNetworkService() }},
+		{Name: "NSE", Value: func(r storage.Resource) string {
+			return r.(*networkservice.Connection).GetNetworkServiceEndpointName()
+		}},
+		{Name: "MECHANISM", Value: func(r storage.Resource) string { return r.(*networkservice.Connection).GetMechanism().GetType() }},
+		{Name: "STATE", Value: func(r storage.Resource) string { return r.(*networkservice.Connection).GetState().String() }},
+		{Name: "SRC-IP", Wide: true, Value: func(r storage.Resource) string {
+			return strings.Join(r.(*networkservice.Connection).GetContext().GetIpContext().GetSrcIpAddrs(), ",")
+		}},
+		{Name: "DST-IP", Wide: true, Value: func(r storage.Resource) string {
+			return strings.Join(r.(*networkservice.Connection).GetContext().GetIpContext().GetDstIpAddrs(), ",")
+		}},
+		{Name: "HOPS", Wide: true, Value: func(r storage.Resource) string {
+			return strconv.Itoa(len(r.(*networkservice.Connection).GetPath().GetPathSegments()))
+		}},
+		{Name: "LABELS", Wide: true, Value: func(r storage.Resource) string {
+			return formatLabels(r.(*networkservice.Connection).GetLabels())
+		}},
+	}
+}
+
+func formatLabels(labels map[string]string) string {
+	var result = make([]string, 0, len(labels))
+	for k, v := range labels {
+		result = append(result, k+"="+v)
+	}
+	sort.Strings(result)
+	return strings.Join(result, ",")
+}
+
+func until(t *timestamppb.Timestamp) string {
+	if t == nil {
+		return ""
+	}
+	var d = time.Until(t.AsTime())
+	if d < 0 {
+		return "expired"
+	}
+	return humanDuration(d)
+}
+
+func since(t *timestamppb.Timestamp) string {
+	if t == nil {
+		return ""
+	}
+	return humanDuration(time.Since(t.AsTime()))
+}
+
+func humanDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < 10*time.Minute:
+		return fmt.Sprintf("%dm%ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 3*time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
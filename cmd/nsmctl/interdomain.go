@@ -0,0 +1,52 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsmctl
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+	"github.com/networkservicemesh/sdk/pkg/tools/interdomain"
+)
+
+// route selects the domain of the interdomain name 'name@domain'. If the domain is stored, the returned context selects it
+// and the name is returned without the domain. Otherwise the name is returned as is: the registry of the current domain
+// resolves it by DNS the same way as floating interdomain names.
+func route(ctx context.Context, name string) (context.Context, string, error) {
+	if interdomain.Not(name) {
+		return ctx, name, nil
+	}
+
+	var domainName = interdomain.Domain(name)
+	if strings.ContainsAny(domainName, `/\`) {
+		return ctx, name, nil
+	}
+
+	var d, err = domain.Load(domainName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ctx, name, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return domain.WithContext(ctx, d), interdomain.Target(name), nil
+}
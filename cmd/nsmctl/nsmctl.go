@@ -19,21 +19,38 @@ package nsmctl
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/networkservicemesh/nsmctl/cmd/apiresources"
+	"github.com/networkservicemesh/nsmctl/cmd/auth"
+	"github.com/networkservicemesh/nsmctl/cmd/check"
+	"github.com/networkservicemesh/nsmctl/cmd/config"
 	"github.com/networkservicemesh/nsmctl/cmd/create"
 	"github.com/networkservicemesh/nsmctl/cmd/delete"
 	"github.com/networkservicemesh/nsmctl/cmd/describe"
+	"github.com/networkservicemesh/nsmctl/cmd/diff"
+	"github.com/networkservicemesh/nsmctl/cmd/edit"
+	"github.com/networkservicemesh/nsmctl/cmd/explain"
 	"github.com/networkservicemesh/nsmctl/cmd/generate"
 	"github.com/networkservicemesh/nsmctl/cmd/get"
+	"github.com/networkservicemesh/nsmctl/cmd/patch"
+	"github.com/networkservicemesh/nsmctl/cmd/trace"
 	"github.com/networkservicemesh/nsmctl/cmd/use"
-	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/domain"
 	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/persistence"
+	"github.com/networkservicemesh/nsmctl/pkg/client"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
 )
 
 // New creates new cmd/nsmctl
 func New() *cobra.Command {
+	var clients = newClientManager()
+	cobra.OnFinalize(func() {
+		_ = clients.Close()
+	})
+
 	nsmctlCmd := &cobra.Command{
 		Use:               "nsmctl",
 		Short:             "NSM command line tool",
@@ -47,13 +64,23 @@ func New() *cobra.Command {
 			fmt.Println("See more information about NSM https://networkservicemesh.io/docs/concepts/enterprise_users/")
 		},
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			var domainName, err = cmd.Flags().GetString("domain")
+			var configPath, err = cmd.Flags().GetString("config")
 			if err != nil {
 				return err
 			}
+			persistence.SetPath(configPath)
+
+			if err = configureClients(cmd, clients); err != nil {
+				return err
+			}
+
+			var domainName string
+			if domainName, err = cmd.Flags().GetString("domain"); err != nil {
+				return err
+			}
 
 			if domainName != "" {
-				v, vErr := persistence.Load[*domain.Domain](domainName)
+				v, vErr := domain.Load(domainName)
 				if vErr != nil {
 					return vErr
 				}
@@ -64,12 +91,21 @@ func New() *cobra.Command {
 		},
 	}
 
-	var storages = defaultResources()
+	var storages = defaultResources(clients)
 
 	nsmctlCmd.AddCommand(get.New(storages))
 	nsmctlCmd.AddCommand(create.New(storages))
 	nsmctlCmd.AddCommand(delete.New(storages))
 	nsmctlCmd.AddCommand(describe.New(storages))
+	nsmctlCmd.AddCommand(edit.New(storages))
+	nsmctlCmd.AddCommand(diff.New(storages))
+	nsmctlCmd.AddCommand(patch.New(storages))
+	nsmctlCmd.AddCommand(apiresources.New(storages))
+	nsmctlCmd.AddCommand(explain.New(storages))
+	nsmctlCmd.AddCommand(trace.New(storages))
+	nsmctlCmd.AddCommand(check.New(clients.current))
+	nsmctlCmd.AddCommand(auth.New(clients.current))
+	nsmctlCmd.AddCommand(config.New())
 	nsmctlCmd.AddCommand(use.New())
 	nsmctlCmd.AddCommand(generate.New())
 
@@ -80,8 +116,46 @@ func New() *cobra.Command {
 
 func addCommonFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP("domain", "d", "", "nsm domain that should be used for control")
+	cmd.Flags().String("config", "", "path of the configuration file, overrides $NSMCTL_CONFIG")
+	cmd.Flags().Duration("request-timeout", 0, "timeout of each request to the domain, e.g. 10s, zero means no timeout")
+	cmd.Flags().Duration("dial-timeout", client.DefaultDialTimeout, "timeout of connecting to the registry or the manager of the domain, zero means no timeout")
+	cmd.Flags().Int("retries", 3, "number of retries of get, list and describe requests that fail because the domain is unavailable")
+	cmd.Flags().Duration("retry-backoff", client.DefaultRetryBackoff, "delay before the first retry, doubled for each next one")
 
 	for _, child := range cmd.Commands() {
 		addCommonFlags(child)
 	}
 }
+
+// configureClients sets options of the clients from the common flags
+func configureClients(cmd *cobra.Command, clients *clientManager) error {
+	var flags = cmd.Flags()
+
+	var requestTimeout, err = flags.GetDuration("request-timeout")
+	if err != nil {
+		return err
+	}
+	var dialTimeout time.Duration
+	if dialTimeout, err = flags.GetDuration("dial-timeout"); err != nil {
+		return err
+	}
+	var retries int
+	if retries, err = flags.GetInt("retries"); err != nil {
+		return err
+	}
+	var backoff time.Duration
+	if backoff, err = flags.GetDuration("retry-backoff"); err != nil {
+		return err
+	}
+
+	if requestTimeout < 0 || dialTimeout < 0 || retries < 0 || backoff < 0 {
+		return errors.New("--request-timeout, --dial-timeout, --retries and --retry-backoff can not be negative")
+	}
+
+	clients.configure(
+		client.WithRequestTimeout(requestTimeout),
+		client.WithDialTimeout(dialTimeout),
+		client.WithRetry(retries, backoff),
+	)
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsmctl
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/codec"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/patch"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+)
+
+// withPatch lets the storage patch resources: the patch is applied to the current resource and only the result is stored
+func withPatch(s *storage.Storage) *storage.Storage {
+	s.Patch = func(ctx context.Context, name string, t patch.Type, data []byte) (storage.Resource, error) {
+		var current, err = s.Get(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		var original any
+		if original, err = codec.ToValue(current); err != nil {
+			return nil, err
+		}
+
+		var b []byte
+		if b, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the patch")
+		}
+		var p any
+		if err = json.Unmarshal(b, &p); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the patch")
+		}
+
+		var merged any
+		if merged, err = patch.Apply(t, original, p); err != nil {
+			return nil, err
+		}
+		if b, err = json.Marshal(merged); err != nil {
+			return nil, errors.Wrapf(err, "failed to patch %v %v", s.Kind, name)
+		}
+
+		var result = s.Create(ctx)
+		if err = codec.UnmarshalYAML(b, result); err != nil {
+			return nil, err
+		}
+		if n := storage.NameOf(result); n != name {
+			return nil, errors.Errorf("name can not be changed from %v to %v", name, n)
+		}
+
+		if err = s.Update(ctx, name, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return s
+}
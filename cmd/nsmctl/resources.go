@@ -18,391 +18,308 @@ package nsmctl
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
-	"fmt"
-	"net"
-	"os"
-	"strconv"
-	"strings"
-	"time"
+	"reflect"
 
-	"github.com/edwarnicke/grpcfd"
-	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
-	"github.com/spiffe/go-spiffe/v2/workloadapi"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/registry"
-	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/domain"
 	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/persistence"
 	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
-	"github.com/networkservicemesh/sdk/pkg/registry/common/grpcmetadata"
-	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
-	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
-	"github.com/networkservicemesh/sdk/pkg/tools/token"
+	"github.com/networkservicemesh/nsmctl/pkg/client"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
 )
 
-func defaultResources() map[string]*storage.Storage {
-	var result = make(map[string]*storage.Storage)
+func defaultResources(clients *clientManager) storage.Registry {
+	var result = make(storage.Registry)
 
-	registerAliases(result, persistence.Storage[*domain.Domain](), "domain", "domains")
-	registerAliases(result, newConnectionsStorage(), "conn", "conns", "connection", "connections")
-	registerAliases(result, newNSStorage(), "networkservice", "networkservices", "netsvc", "netsvcs")
-	registerAliases(result, newNSEStorage(), "networkserviceendpoints", "endpoints", "networkserviceendpoint", "endpoint", "nse", "nses")
+	result.Register(withColumns(newDomainStorage(), domainColumns()...), "domains")
+	result.Register(withColumns(newConnectionsStorage(clients), connectionColumns()...), "connections", "conn", "conns")
+	result.Register(withColumns(withPatch(newNSStorage(clients)), nsColumns()...), "networkservices", "netsvc", "netsvcs")
+	result.Register(withColumns(withPatch(newNSEStorage(clients)), nseColumns()...), "networkserviceendpoints", "endpoint", "endpoints", "nse", "nses")
 
 	return result
 }
 
-func registerAliases(m map[string]*storage.Storage, v *storage.Storage, aliases ...string) {
-	for _, k := range aliases {
-		m[k] = v
+// newDomainStorage keeps domains in the nsmctl configuration. New domains start from the defaults of domain.New
+// and are validated before they are stored, a new default domain replaces the previous one.
+func newDomainStorage() *storage.Storage {
+	var result = persistence.Storage[*domain.Domain]()
+	result.Create = func(ctx context.Context) storage.Resource {
+		return domain.New("")
 	}
+	result.Update = func(ctx context.Context, s string, r storage.Resource) error {
+		var d = r.(*domain.Domain)
+		if d.Name == "" {
+			d.Name = s
+		}
+		if d.Name != s {
+			return errors.New("name of the domain " + d.Name + " doesn't match " + s)
+		}
+		if err := d.Validate(); err != nil {
+			return err
+		}
+		return domain.Store(d)
+	}
+	return result
 }
 
-func newConnectionsStorage() *storage.Storage {
+func newConnectionsStorage(clients *clientManager) *storage.Storage {
 	return &storage.Storage{
+		Kind:      "connection",
+		Type:      reflect.TypeOf(&networkservice.Connection{}),
+		PerDomain: true,
 		Get: func(ctx context.Context, s string) (storage.Resource, error) {
-			var cc grpc.ClientConnInterface
-			var d, err = domain.Current()
-			if err != nil {
-				return nil, err
-			}
-			cc, err = dial(ctx, d, d.ManagerService)
-			if err != nil {
-				return nil, err
-			}
-			monitorCtx, cancel := context.WithCancel(ctx)
-			defer cancel()
-			var monitorConnectionClient = networkservice.NewMonitorConnectionClient(cc)
-			stream, err := monitorConnectionClient.MonitorConnections(monitorCtx, &networkservice.MonitorScopeSelector{PathSegments: []*networkservice.PathSegment{
-				{
-					Id: s,
-				},
-			}})
-			if err != nil {
-				return nil, err
-			}
-			resp, err := stream.Recv()
+			var c, err = clients.current(ctx)
 			if err != nil {
 				return nil, err
 			}
-			if v, ok := resp.Connections[s]; ok {
-				return v, nil
-			}
-			return nil, errors.New("connection with id " + s + " is not found")
-		},
-		Delete: func(ctx context.Context, s string) error {
-			return errors.New("connections are readonly")
-		},
-		Create: func(ctx context.Context) storage.Resource {
-			return new(registry.NetworkServiceEndpoint)
+			return resourceOf(c.Connections().Get(ctx, s))
 		},
 		List: func(ctx context.Context) ([]storage.Resource, error) {
-			var cc grpc.ClientConnInterface
-			var d, err = domain.Current()
+			var c, err = clients.current(ctx)
 			if err != nil {
 				return nil, err
 			}
-			cc, err = dial(ctx, d, d.ManagerService)
-			if err != nil {
-				return nil, err
-			}
-			monitorCtx, cancel := context.WithCancel(ctx)
-			defer cancel()
-			var monitorConnectionClient = networkservice.NewMonitorConnectionClient(cc)
-			stream, err := monitorConnectionClient.MonitorConnections(monitorCtx, &networkservice.MonitorScopeSelector{PathSegments: []*networkservice.PathSegment{
-				{},
-			}})
-			if err != nil {
-				return nil, err
-			}
-			resp, err := stream.Recv()
+			return resourcesOf(c.Connections().List(ctx))
+		},
+		Labels: func(r storage.Resource) map[string]map[string]string {
+			var conn = r.(*networkservice.Connection)
+			return map[string]map[string]string{conn.GetNetworkService(): conn.GetLabels()}
+		},
+		Watch: func(ctx context.Context, handler func(*storage.Event) error) error {
+			var c, err = clients.current(ctx)
 			if err != nil {
-				return nil, err
-			}
-
-			var result []storage.Resource
-
-			for _, item := range resp.Connections {
-				result = append(result, item)
+				return err
 			}
-
-			return result, nil
+			var w = make(watchState)
+			return c.Connections().Watch(ctx, func(event *networkservice.ConnectionEvent) error {
+				for id, item := range event.GetConnections() {
+					if handlerErr := handler(w.event(id, item, event.GetType() == networkservice.ConnectionEventType_DELETE)); handlerErr != nil {
+						return handlerErr
+					}
+				}
+				return nil
+			})
 		},
 	}
 }
 
-func newNSStorage() *storage.Storage {
+func newNSStorage(clients *clientManager) *storage.Storage {
 	return &storage.Storage{
+		Kind:         "networkservice",
+		Type:         reflect.TypeOf(&registry.NetworkService{}),
+		ServerFields: []string{"pathIds"},
+		PerDomain:    true,
 		Get: func(ctx context.Context, s string) (storage.Resource, error) {
-			var cc grpc.ClientConnInterface
-			var d, err = domain.Current()
-			if err != nil {
+			var target string
+			var err error
+			if ctx, target, err = route(ctx, s); err != nil {
 				return nil, err
 			}
-			cc, err = dial(ctx, d, d.RegistryService)
-			if err != nil {
+			var c *client.Client
+			if c, err = clients.current(ctx); err != nil {
 				return nil, err
 			}
-			var nsClient = registry.NewNetworkServiceRegistryClient(cc)
-
-			stream, err := nsClient.Find(ctx, &registry.NetworkServiceQuery{
-				NetworkService: &registry.NetworkService{
-					Name: s,
-				}})
-			if err != nil {
-				return nil, err
+			var ns *registry.NetworkService
+			if ns, err = c.NetworkServices().Get(ctx, target); err == nil {
+				// The resource keeps the interdomain name it was requested by
+				ns.Name = s
 			}
-			var list = registry.ReadNetworkServiceList(stream)
-
-			if len(list) == 0 {
-				return nil, errors.New(s + " is not found")
-			}
-			return list[0], nil
+			return resourceOf(ns, err)
 		},
 		Delete: func(ctx context.Context, s string) error {
-			var cc grpc.ClientConnInterface
-			var d, err = domain.Current()
-			if err != nil {
+			var target string
+			var err error
+			if ctx, target, err = route(ctx, s); err != nil {
 				return err
 			}
-			cc, err = dial(ctx, d, d.RegistryService)
-			if err != nil {
+			var c *client.Client
+			if c, err = clients.current(ctx); err != nil {
 				return err
 			}
-			var nsClient = next.NewNetworkServiceRegistryClient(
-				grpcmetadata.NewNetworkServiceRegistryClient(),
-				registry.NewNetworkServiceRegistryClient(cc),
-			)
-
-			_, err = nsClient.Unregister(ctx, &registry.NetworkService{Name: s})
-			return err
+			return c.NetworkServices().Unregister(ctx, target)
 		},
 		Create: func(ctx context.Context) storage.Resource {
 			return new(registry.NetworkService)
 		},
 		Update: func(ctx context.Context, s string, r storage.Resource) error {
-			var cc grpc.ClientConnInterface
-			var d, err = domain.Current()
-			if err != nil {
+			var target string
+			var err error
+			if ctx, target, err = route(ctx, s); err != nil {
 				return err
 			}
-			cc, err = dial(ctx, d, d.RegistryService)
-			if err != nil {
+			var c *client.Client
+			if c, err = clients.current(ctx); err != nil {
 				return err
 			}
-			var nsClient = next.NewNetworkServiceRegistryClient(
-				grpcmetadata.NewNetworkServiceRegistryClient(),
-				registry.NewNetworkServiceRegistryClient(cc),
-			)
-
-			_, err = nsClient.Register(ctx, r.(*registry.NetworkService))
-
+			var ns = proto.Clone(r.(*registry.NetworkService)).(*registry.NetworkService)
+			if ns.GetName() == s {
+				ns.Name = target
+			}
+			_, err = c.NetworkServices().Register(ctx, ns)
 			return err
 		},
 		List: func(ctx context.Context) ([]storage.Resource, error) {
-			var cc grpc.ClientConnInterface
-			var d, err = domain.Current()
-			if err != nil {
-				return nil, err
-			}
-			cc, err = dial(ctx, d, d.RegistryService)
+			var c, err = clients.current(ctx)
 			if err != nil {
 				return nil, err
 			}
-			var nseClient = registry.NewNetworkServiceRegistryClient(cc)
-
-			stream, err := nseClient.Find(ctx,
-				&registry.NetworkServiceQuery{
-					NetworkService: &registry.NetworkService{},
-				},
-			)
+			return resourcesOf(c.NetworkServices().List(ctx, nil))
+		},
+		Watch: func(ctx context.Context, handler func(*storage.Event) error) error {
+			var c, err = clients.current(ctx)
 			if err != nil {
-				return nil, err
-			}
-			var list = registry.ReadNetworkServiceList(stream)
-			var result []storage.Resource
-
-			for _, item := range list {
-				result = append(result, item)
+				return err
 			}
-
-			return result, nil
+			var w = make(watchState)
+			return c.NetworkServices().Watch(ctx, nil, func(resp *registry.NetworkServiceResponse) error {
+				var ns = resp.GetNetworkService()
+				return handler(w.event(ns.GetName(), ns, resp.GetDeleted()))
+			})
 		},
 	}
 }
 
-func newNSEStorage() *storage.Storage {
+func newNSEStorage(clients *clientManager) *storage.Storage {
 	return &storage.Storage{
+		Kind:         "networkserviceendpoint",
+		Type:         reflect.TypeOf(&registry.NetworkServiceEndpoint{}),
+		ServerFields: []string{"url", "expirationTime", "initialRegistrationTime", "pathIds"},
+		PerDomain:    true,
 		Get: func(ctx context.Context, s string) (storage.Resource, error) {
-			var cc grpc.ClientConnInterface
-			var d, err = domain.Current()
-			if err != nil {
+			var target string
+			var err error
+			if ctx, target, err = route(ctx, s); err != nil {
 				return nil, err
 			}
-			cc, err = dial(ctx, d, d.RegistryService)
-			if err != nil {
+			var c *client.Client
+			if c, err = clients.current(ctx); err != nil {
 				return nil, err
 			}
-			var nseClient = next.NewNetworkServiceEndpointRegistryClient(
-				grpcmetadata.NewNetworkServiceEndpointRegistryClient(),
-				registry.NewNetworkServiceEndpointRegistryClient(cc),
-			)
-
-			stream, err := nseClient.Find(ctx, &registry.NetworkServiceEndpointQuery{
-				NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{
-					Name: s,
-				}})
-			if err != nil {
-				return nil, err
-			}
-			var list = registry.ReadNetworkServiceEndpointList(stream)
-
-			if len(list) == 0 {
-				return nil, errors.New(s + " is not found")
+			var nse *registry.NetworkServiceEndpoint
+			if nse, err = c.NetworkServiceEndpoints().Get(ctx, target); err == nil {
+				// The resource keeps the interdomain name it was requested by
+				nse.Name = s
 			}
-			return list[0], nil
+			return resourceOf(nse, err)
 		},
 		Delete: func(ctx context.Context, s string) error {
-			var cc grpc.ClientConnInterface
-			var d, err = domain.Current()
-			if err != nil {
+			var target string
+			var err error
+			if ctx, target, err = route(ctx, s); err != nil {
 				return err
 			}
-			cc, err = dial(ctx, d, d.RegistryService)
-			if err != nil {
+			var c *client.Client
+			if c, err = clients.current(ctx); err != nil {
 				return err
 			}
-			var nseClient = next.NewNetworkServiceEndpointRegistryClient(
-				grpcmetadata.NewNetworkServiceEndpointRegistryClient(),
-				registry.NewNetworkServiceEndpointRegistryClient(cc),
-			)
-			_, err = nseClient.Unregister(ctx, &registry.NetworkServiceEndpoint{Name: s})
-			return err
+			return c.NetworkServiceEndpoints().Unregister(ctx, target)
 		},
 		Create: func(ctx context.Context) storage.Resource {
 			return new(registry.NetworkServiceEndpoint)
 		},
 		List: func(ctx context.Context) ([]storage.Resource, error) {
-			var cc grpc.ClientConnInterface
-			var d, err = domain.Current()
+			var c, err = clients.current(ctx)
 			if err != nil {
 				return nil, err
 			}
-			cc, err = dial(ctx, d, d.RegistryService)
-			if err != nil {
-				return nil, err
+			return resourcesOf(c.NetworkServiceEndpoints().List(ctx, nil))
+		},
+		Labels: func(r storage.Resource) map[string]map[string]string {
+			var nse = r.(*registry.NetworkServiceEndpoint)
+			var result = make(map[string]map[string]string)
+			for _, service := range nse.GetNetworkServiceNames() {
+				result[service] = nse.GetNetworkServiceLabels()[service].GetLabels()
 			}
-			var nseClient = registry.NewNetworkServiceEndpointRegistryClient(cc)
-
-			stream, err := nseClient.Find(ctx,
-				&registry.NetworkServiceEndpointQuery{
-					NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{},
-				},
-			)
+			return result
+		},
+		Find: func(ctx context.Context, q *storage.Query) ([]storage.Resource, error) {
+			var c, err = clients.current(ctx)
 			if err != nil {
 				return nil, err
 			}
-			var list = registry.ReadNetworkServiceEndpointList(stream)
-			var result []storage.Resource
-
-			for _, item := range list {
-				result = append(result, item)
+			if q.NetworkService == "" {
+				// The registry matches labels per network service, so it can't select from all services at once
+				return resourcesOf(c.NetworkServiceEndpoints().List(ctx, nil))
 			}
-
-			return result, nil
+			var query = &registry.NetworkServiceEndpoint{
+				NetworkServiceNames: []string{q.NetworkService},
+			}
+			if equalities := q.Labels.Equalities(); len(equalities) > 0 {
+				query.NetworkServiceLabels = map[string]*registry.NetworkServiceLabels{
+					q.NetworkService: {Labels: equalities},
+				}
+			}
+			return resourcesOf(c.NetworkServiceEndpoints().List(ctx, query))
 		},
 		Update: func(ctx context.Context, s string, r storage.Resource) error {
-			var cc grpc.ClientConnInterface
-			var d, err = domain.Current()
-			if err != nil {
+			var target string
+			var err error
+			if ctx, target, err = route(ctx, s); err != nil {
 				return err
 			}
-			cc, err = dial(ctx, d, d.RegistryService)
-			if err != nil {
+			var c *client.Client
+			if c, err = clients.current(ctx); err != nil {
 				return err
 			}
-			var nseClient = next.NewNetworkServiceEndpointRegistryClient(
-				grpcmetadata.NewNetworkServiceEndpointRegistryClient(),
-				registry.NewNetworkServiceEndpointRegistryClient(cc),
-			)
-
-			_, err = nseClient.Register(ctx, r.(*registry.NetworkServiceEndpoint))
-
+			var nse = proto.Clone(r.(*registry.NetworkServiceEndpoint)).(*registry.NetworkServiceEndpoint)
+			if nse.GetName() == s {
+				nse.Name = target
+			}
+			_, err = c.NetworkServiceEndpoints().Register(ctx, nse)
 			return err
 		},
+		Watch: func(ctx context.Context, handler func(*storage.Event) error) error {
+			var c, err = clients.current(ctx)
+			if err != nil {
+				return err
+			}
+			var w = make(watchState)
+			return c.NetworkServiceEndpoints().Watch(ctx, nil, func(resp *registry.NetworkServiceEndpointResponse) error {
+				var nse = resp.GetNetworkServiceEndpoint()
+				return handler(w.event(nse.GetName(), nse, resp.GetDeleted()))
+			})
+		},
 	}
 }
 
-func dial(ctx context.Context, d *domain.Domain, target string) (grpc.ClientConnInterface, error) {
-	if !strings.Contains(target, ":") {
-		var dialer net.Dialer
-		var r = net.Resolver{
-			PreferGo: true,
-			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
-				if d.DNSServerAddress != "" {
-					return dialer.DialContext(ctx, network, d.DNSServerAddress)
-				}
-				return dialer.DialContext(ctx, network, address)
-			},
-		}
-		serviceDomain := d.FQDN(target)
-
-		_, records, err := r.LookupSRV(ctx, "", "", serviceDomain)
-		if err != nil {
-			return nil, err
-		}
-		if len(records) == 0 {
-			return nil, errors.New("resolver.LookupSERV return empty result")
-		}
-		port := strconv.Itoa(int(records[0].Port))
-
-		ips, err := r.LookupIPAddr(ctx, serviceDomain)
-		if err != nil {
-			return nil, err
-		}
-		if len(ips) == 0 {
-			return nil, errors.New("resolver.LookupIPAddr return empty result")
-		}
-		ipAddr := ips[0].IP
-
-		target = fmt.Sprintf("%v:%v", ipAddr.String(), port)
+// resourceOf converts the result of the client to the result of the storage
+func resourceOf[T storage.Resource](r T, err error) (storage.Resource, error) {
+	var notFound *client.NotFoundError
+	if errors.As(err, &notFound) {
+		return nil, &storage.NotFoundError{Kind: notFound.Kind, Name: notFound.Name}
 	}
-
-	if os.Getenv(workloadapi.SocketEnv) == "" {
-		_ = os.Setenv(workloadapi.SocketEnv, "unix:///tmp/spire-agent/public/api.sock")
+	if err != nil {
+		return nil, err
 	}
+	return r, nil
+}
 
-	var dialOptions []grpc.DialOption
-
-	if d.IsInsecure {
-		dialOptions = append(dialOptions, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	} else {
-		source, err := workloadapi.NewX509Source(ctx)
-		if err != nil {
-			return nil, err
-		}
-
-		tlsClientConfig := tlsconfig.MTLSClientConfig(source, source, tlsconfig.AuthorizeAny())
-		tlsClientConfig.MinVersion = tls.VersionTLS12
-
-		dialOptions = append(dialOptions,
-			grpc.WithTransportCredentials(
-				grpcfd.TransportCredentials(credentials.NewTLS(tlsClientConfig))),
-			grpc.WithDefaultCallOptions(
-				grpc.PerRPCCredentials(token.NewPerRPCCredentials(spiffejwt.TokenGeneratorFunc(source, time.Hour))),
-			),
-			grpcfd.WithChainStreamInterceptor(),
-			grpcfd.WithChainUnaryInterceptor(),
-		)
+// resourcesOf converts the list returned by the client to the list of the storage
+func resourcesOf[T storage.Resource](list []T, err error) ([]storage.Resource, error) {
+	if err != nil {
+		return nil, err
+	}
+	var result []storage.Resource
+	for _, item := range list {
+		result = append(result, item)
 	}
+	return result, nil
+}
 
-	dialOptions = append([]grpc.DialOption{
-		grpc.WithBlock(),
-	}, dialOptions...)
+// watchState remembers resources seen by a watch stream to tell additions from modifications
+type watchState map[string]struct{}
 
-	return grpc.DialContext(ctx, target, dialOptions...)
+func (w watchState) event(name string, r storage.Resource, deleted bool) *storage.Event {
+	if deleted {
+		delete(w, name)
+		return &storage.Event{Type: storage.Deleted, Resource: r}
+	}
+	if _, ok := w[name]; ok {
+		return &storage.Event{Type: storage.Modified, Resource: r}
+	}
+	w[name] = struct{}{}
+	return &storage.Event{Type: storage.Added, Resource: r}
 }
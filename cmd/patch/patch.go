@@ -0,0 +1,98 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package patch provides control to change a part of resources
+package patch
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/patch"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+)
+
+// New creates a new instance of cobra.Command that allows to patch resources
+func New(storages map[string]*storage.Storage) *cobra.Command {
+	var r = &cobra.Command{
+		Use:               "patch",
+		Short:             "Changes a part of a NSM resource",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `Applies the patch to the current resource and stores the result, fields that are not in the patch stay as is.
+Expects type and name of the resource. The patch is passed in JSON or YAML by -p or --patch-file.
+merge type is JSON merge patch: maps are merged, null removes a field, lists are replaced.
+strategic type works as merge, but lists of values, e.g. network service names, are merged as sets.
+	`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("resource type and name are required")
+			}
+
+			var resourceType, name = args[0], args[1]
+
+			var s, ok = storages[resourceType]
+
+			if !ok {
+				return errors.New("unknown type " + resourceType)
+			}
+
+			if err := s.Check(storage.VerbPatch); err != nil {
+				return err
+			}
+
+			var data, patchFile, patchType string
+			var err error
+			if data, err = cmd.Flags().GetString("patch"); err != nil {
+				return err
+			}
+			if patchFile, err = cmd.Flags().GetString("patch-file"); err != nil {
+				return err
+			}
+			if patchType, err = cmd.Flags().GetString("type"); err != nil {
+				return err
+			}
+
+			switch {
+			case data != "" && patchFile != "":
+				return errors.New("--patch and --patch-file can not be used together")
+			case patchFile != "":
+				// #nosec
+				var b, readErr = os.ReadFile(patchFile)
+				if readErr != nil {
+					return errors.WithStack(readErr)
+				}
+				data = string(b)
+			case data == "":
+				return errors.New("--patch or --patch-file is required")
+			}
+
+			if _, err = s.Patch(cmd.Context(), name, patch.Type(patchType), []byte(data)); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), s.Kind+"/"+name+" patched")
+			return nil
+		},
+	}
+	r.Flags().StringP("patch", "p", "", "the patch in JSON or YAML, e.g. -p '{\"networkServiceLabels\":{\"ns\":{\"labels\":{\"app\":\"b\"}}}}'")
+	r.Flags().String("patch-file", "", "file with the patch")
+	r.Flags().String("type", string(patch.Merge), fmt.Sprintf("type of the patch, one of %v", patch.Types))
+
+	return r
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trace provides control to follow the path of a connection
+package trace
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/trace"
+)
+
+// New creates a new cobra.Command instance that renders the path of a connection hop by hop
+func New(storages map[string]*storage.Storage) *cobra.Command {
+	return &cobra.Command{
+		Use:               "trace",
+		Short:             "renders the path of a connection hop by hop",
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+		Long: `Renders the path of a connection from the client to the endpoint: the name, the id, the token expiration
+and the metrics of each hop. The current hop of the path is marked with '*', the mechanism belongs to it.
+The IP context is shown for both ends of the connection. The id may be the id of any hop, e.g. the id requested
+by the client: 'nsmctl trace connection my-conn-id'.
+	`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("expected 'trace connection ID'")
+			}
+
+			var s, ok = storages[args[0]]
+			if !ok {
+				return errors.New("unknown type " + args[0])
+			}
+			if s.Kind != "connection" {
+				return errors.Errorf("only connections can be traced, %v is not a connection", args[0])
+			}
+
+			var conn, err = find(cmd.Context(), s, args[1])
+			if err != nil {
+				return err
+			}
+			return trace.Print(cmd.OutOrStdout(), conn, time.Now())
+		},
+	}
+}
+
+// find returns the connection with the id. The manager knows connections by its own ids, so the connection is also
+// looked up by the ids of the other hops, e.g. the id the client has requested.
+func find(ctx context.Context, s *storage.Storage, id string) (*networkservice.Connection, error) {
+	var r, err = s.Get(ctx, id)
+	if err == nil {
+		return r.(*networkservice.Connection), nil
+	}
+	if !storage.IsNotFound(err) {
+		return nil, err
+	}
+
+	var list, listErr = s.List(ctx)
+	if listErr != nil {
+		return nil, listErr
+	}
+	for _, item := range list {
+		var conn = item.(*networkservice.Connection)
+		for _, segment := range conn.GetPath().GetPathSegments() {
+			if segment.GetId() == id {
+				return conn, nil
+			}
+		}
+	}
+	return nil, err
+}
@@ -18,13 +18,11 @@
 package use
 
 import (
-	"context"
 	"errors"
 
 	"github.com/spf13/cobra"
 
-	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/domain"
-	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/persistence"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
 )
 
 // New creates a new cobra.Command instance that allows to manage current NSM domain
@@ -41,35 +39,7 @@ func New() *cobra.Command {
 			if args[0] != "domain" {
 				return errors.New("unknown type " + args[0])
 			}
-			var name = args[1]
-			var domainStorage = persistence.Storage[*domain.Domain]()
-
-			rawDomain, err := domainStorage.Get(cmd.Context(), name)
-			if err != nil {
-				return err
-			}
-
-			domains, err := domainStorage.List(context.Background())
-
-			if err != nil {
-				return err
-			}
-
-			for _, d := range domains {
-				var castedDomain = d.(*domain.Domain)
-				if castedDomain.IsDefault {
-					castedDomain.IsDefault = false
-				}
-				_ = domainStorage.Update(context.Background(), castedDomain.Name, castedDomain)
-			}
-
-			var d = rawDomain.(*domain.Domain)
-
-			d.IsDefault = true
-
-			_ = domainStorage.Update(context.Background(), d.Name, d)
-
-			return nil
+			return domain.Use(args[1])
 		},
 	}
 }
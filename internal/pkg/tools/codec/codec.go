@@ -0,0 +1,106 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package codec converts NSM resources to JSON and YAML documents.
+// Protobuf messages are encoded with protojson so the output uses canonical field names.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+// MarshalJSON returns indented JSON representation of the value
+func MarshalJSON(v any) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok {
+		b, err := protojson.Marshal(m)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal %T", v)
+		}
+		// protojson output is deliberately unstable, so indent it on our own
+		var out bytes.Buffer
+		if err = json.Indent(&out, b, "", "  "); err != nil {
+			return nil, errors.Wrapf(err, "failed to indent %T", v)
+		}
+		return out.Bytes(), nil
+	}
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// MarshalYAML returns YAML representation of the value
+func MarshalYAML(v any) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok {
+		b, err := protojson.Marshal(m)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal %T", v)
+		}
+		return yaml.JSONToYAML(b)
+	}
+	return yamlv2.Marshal(v)
+}
+
+// ToPopulatedValue works as ToValue, but fields of protobuf messages with default values are included,
+// e.g. the state UP of a connection, so the values can be compared
+func ToPopulatedValue(v any) (any, error) {
+	var m, ok = v.(proto.Message)
+	if !ok {
+		return ToValue(v)
+	}
+	b, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal %T", v)
+	}
+	var result any
+	if err = json.Unmarshal(b, &result); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert %T", v)
+	}
+	return result, nil
+}
+
+// UnmarshalYAML decodes YAML document into the value. Protobuf messages accept the field names of protojson,
+// so documents printed by MarshalYAML can be read back.
+func UnmarshalYAML(b []byte, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		j, err := yaml.YAMLToJSON(b)
+		if err != nil {
+			return errors.Wrapf(err, "failed to convert YAML to JSON for %T", v)
+		}
+		if err = protojson.Unmarshal(j, m); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal %T", v)
+		}
+		return nil
+	}
+	return yamlv2.Unmarshal(b, v)
+}
+
+// ToValue converts the value to the generic form produced by encoding/json: maps, slices, strings, numbers and bools
+func ToValue(v any) (any, error) {
+	b, err := MarshalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	var result any
+	if err = json.Unmarshal(b, &result); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert %T", v)
+	}
+	return result, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crd converts NSM registry resources to the custom resources of the Kubernetes registry and back.
+package crd
+
+import (
+	"encoding/json"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+)
+
+// APIVersion is the API version of NSM custom resources
+const APIVersion = "networkservicemesh.io/v1"
+
+// Kinds of NSM custom resources
+const (
+	NetworkService         = "NetworkService"
+	NetworkServiceEndpoint = "NetworkServiceEndpoint"
+)
+
+// runtimeFields are filled by the registry, so they are not a part of the definition.
+// The name is a part of the metadata.
+var runtimeFields = []string{"name", "expiration_time", "initial_registration_time", "path_ids"}
+
+// Marshal returns the custom resource that represents the registry resource
+func Marshal(v any) (map[string]any, error) {
+	var kind string
+	var name string
+
+	switch r := v.(type) {
+	case *registry.NetworkService:
+		kind, name = NetworkService, r.GetName()
+	case *registry.NetworkServiceEndpoint:
+		kind, name = NetworkServiceEndpoint, r.GetName()
+	default:
+		return nil, errors.Errorf("%T can not be represented as a custom resource", v)
+	}
+
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(v.(proto.Message))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal %v %v", kind, name)
+	}
+	var spec = make(map[string]any)
+	if err = json.Unmarshal(b, &spec); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %v %v", kind, name)
+	}
+	for _, field := range runtimeFields {
+		delete(spec, field)
+	}
+
+	return map[string]any{
+		"apiVersion": APIVersion,
+		"kind":       kind,
+		"metadata": map[string]any{
+			"name": name,
+		},
+		"spec": spec,
+	}, nil
+}
+
+// Unmarshal converts the custom resource without 'apiVersion' and 'kind' fields to the JSON document of the registry resource
+func Unmarshal(apiVersion, kind string, data []byte) ([]byte, error) {
+	if apiVersion != APIVersion {
+		return nil, errors.Errorf("unsupported apiVersion %v, expected %v", apiVersion, APIVersion)
+	}
+	if kind != NetworkService && kind != NetworkServiceEndpoint {
+		return nil, errors.Errorf("unsupported kind %v, expected %v or %v", kind, NetworkService, NetworkServiceEndpoint)
+	}
+
+	b, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %v", kind)
+	}
+
+	var cr struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec map[string]any `json:"spec"`
+	}
+	if err = json.Unmarshal(b, &cr); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %v", kind)
+	}
+	if cr.Metadata.Name == "" {
+		return nil, errors.Errorf("metadata.name of %v is required", kind)
+	}
+
+	if cr.Spec == nil {
+		cr.Spec = make(map[string]any)
+	}
+	for _, field := range runtimeFields {
+		delete(cr.Spec, field)
+	}
+	cr.Spec["name"] = cr.Metadata.Name
+
+	return json.Marshal(cr.Spec)
+}
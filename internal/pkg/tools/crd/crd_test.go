@@ -0,0 +1,82 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/codec"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/crd"
+)
+
+func TestMarshal(t *testing.T) {
+	var cr, err = crd.Marshal(&registry.NetworkServiceEndpoint{
+		Name:                "nse-1",
+		NetworkServiceNames: []string{"ns-1"},
+		Url:                 "tcp://10.0.0.1:5001",
+		ExpirationTime:      timestamppb.Now(),
+	})
+	require.NoError(t, err)
+
+	b, err := codec.MarshalYAML(cr)
+	require.NoError(t, err)
+	require.Equal(t, `apiVersion: networkservicemesh.io/v1
+kind: NetworkServiceEndpoint
+metadata:
+  name: nse-1
+spec:
+  network_service_names:
+  - ns-1
+  url: tcp://10.0.0.1:5001
+`, string(b))
+
+	_, err = crd.Marshal(&registry.NetworkServiceQuery{})
+	require.Error(t, err)
+}
+
+func TestUnmarshal(t *testing.T) {
+	var b, err = crd.Unmarshal(crd.APIVersion, crd.NetworkService, []byte(`
+metadata:
+  name: ns-1
+  namespace: nsm-system
+spec:
+  payload: ETHERNET
+  matches:
+  - source_selector: {app: a}
+  path_ids: [id]
+`))
+	require.NoError(t, err)
+
+	var ns = new(registry.NetworkService)
+	require.NoError(t, protojson.Unmarshal(b, ns))
+	require.True(t, proto.Equal(&registry.NetworkService{
+		Name:    "ns-1",
+		Payload: "ETHERNET",
+		Matches: []*registry.Match{{SourceSelector: map[string]string{"app": "a"}}},
+	}, ns))
+
+	_, err = crd.Unmarshal("v1", crd.NetworkService, []byte("metadata: {name: ns-1}"))
+	require.Error(t, err)
+	_, err = crd.Unmarshal(crd.APIVersion, crd.NetworkService, []byte("spec: {}"))
+	require.Error(t, err)
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package explain documents fields of resources. Protobuf messages are described by their descriptors
+// with the field names used by JSON and YAML documents, other structs are described by reflection.
+package explain
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Field describes a field of a resource
+type Field struct {
+	// Name is the name of the field in JSON and YAML documents
+	Name string
+	// Type is the type of the field, e.g. string, []string or map[string]NetworkServiceLabels
+	Type string
+	// Values lists the allowed values of enum fields
+	Values []string
+	// Fields are the nested fields of messages, lists of messages and maps of messages
+	Fields []*Field
+
+	aliases []string
+}
+
+// Describe returns the description of the resource type
+func Describe(t reflect.Type) (*Field, error) {
+	if t == nil {
+		return nil, errors.New("type of the resource is unknown")
+	}
+
+	var elem = t
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	if m, ok := reflect.New(elem).Interface().(proto.Message); ok {
+		var md = m.ProtoReflect().Descriptor()
+		return &Field{
+			Name:   string(md.Name()),
+			Type:   string(md.Name()),
+			Fields: messageFields(md, map[protoreflect.FullName]bool{}),
+		}, nil
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return nil, errors.Errorf("%v can not be explained", t)
+	}
+
+	return &Field{
+		Name:   elem.Name(),
+		Type:   elem.Name(),
+		Fields: structFields(elem),
+	}, nil
+}
+
+// Lookup returns the nested field by the path, e.g. networkServiceLabels.labels
+func (f *Field) Lookup(path ...string) (*Field, error) {
+	var result = f
+
+	for i, name := range path {
+		var next *Field
+		for _, child := range result.Fields {
+			if child.matches(name) {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return nil, errors.Errorf("field %v doesn't exist in %v", strings.Join(path[:i+1], "."), f.Name)
+		}
+		result = next
+	}
+
+	return result, nil
+}
+
+func (f *Field) matches(name string) bool {
+	if strings.EqualFold(f.Name, name) {
+		return true
+	}
+	for _, alias := range f.aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Print prints the field of the resource kind. The nested fields are printed with all their descendants if recursive is true.
+func Print(w io.Writer, kind string, path []string, f *Field, recursive bool) error {
+	var tw = tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+
+	_, _ = fmt.Fprintf(tw, "KIND:\t%v\n", kind)
+	if len(path) > 0 {
+		_, _ = fmt.Fprintf(tw, "FIELD:\t%v <%v>\n", strings.Join(path, "."), f.Type)
+	}
+	if len(f.Values) > 0 {
+		_, _ = fmt.Fprintf(tw, "VALUES:\t%v\n", strings.Join(f.Values, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(f.Fields) == 0 {
+		return nil
+	}
+
+	_, _ = fmt.Fprint(w, "\nFIELDS:\n")
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	printFields(tw, f.Fields, 1, recursive)
+	return tw.Flush()
+}
+
+func printFields(w io.Writer, fields []*Field, depth int, recursive bool) {
+	var indent = strings.Repeat("   ", depth)
+
+	for _, f := range fields {
+		var line = indent + f.Name + "\t<" + f.Type + ">"
+		if len(f.Values) > 0 {
+			line += "\t" + strings.Join(f.Values, ", ")
+		}
+		_, _ = fmt.Fprintln(w, line)
+		if recursive {
+			printFields(w, f.Fields, depth+1, recursive)
+		}
+	}
+}
+
+func messageFields(md protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) []*Field {
+	if seen[md.FullName()] {
+		return nil
+	}
+	seen[md.FullName()] = true
+	defer delete(seen, md.FullName())
+
+	var result []*Field
+
+	for i := 0; i < md.Fields().Len(); i++ {
+		var fd = md.Fields().Get(i)
+		var f = &Field{
+			Name:    fd.JSONName(),
+			Type:    fieldType(fd),
+			aliases: []string{string(fd.Name())},
+		}
+
+		var value = fd
+		if fd.IsMap() {
+			value = fd.MapValue()
+		}
+		switch {
+		case value.Enum() != nil:
+			for j := 0; j < value.Enum().Values().Len(); j++ {
+				f.Values = append(f.Values, string(value.Enum().Values().Get(j).Name()))
+			}
+		case value.Message() != nil && wellKnownType(value.Message()) == "":
+			f.Fields = messageFields(value.Message(), seen)
+		}
+
+		result = append(result, f)
+	}
+
+	return result
+}
+
+func fieldType(fd protoreflect.FieldDescriptor) string {
+	switch {
+	case fd.IsMap():
+		return "map[" + kindName(fd.MapKey()) + "]" + kindName(fd.MapValue())
+	case fd.IsList():
+		return "[]" + kindName(fd)
+	default:
+		return kindName(fd)
+	}
+}
+
+func kindName(fd protoreflect.FieldDescriptor) string {
+	switch {
+	case fd.Message() != nil:
+		if t := wellKnownType(fd.Message()); t != "" {
+			return t
+		}
+		return string(fd.Message().Name())
+	case fd.Enum() != nil:
+		return string(fd.Enum().Name())
+	default:
+		return fd.Kind().String()
+	}
+}
+
+// wellKnownType returns the JSON representation of well known types
+func wellKnownType(md protoreflect.MessageDescriptor) string {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return "timestamp"
+	case "google.protobuf.Duration":
+		return "duration"
+	}
+	return ""
+}
+
+func structFields(t reflect.Type) []*Field {
+	var result []*Field
+
+	for i := 0; i < t.NumField(); i++ {
+		var sf = t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		var f = &Field{
+			// yaml.v2 uses the name of the yaml tag or the field name in lower case
+			Name:    strings.ToLower(sf.Name),
+			Type:    sf.Type.String(),
+			aliases: []string{sf.Name},
+		}
+		if tag := strings.Split(sf.Tag.Get("yaml"), ",")[0]; tag != "" && tag != "-" {
+			f.Name = tag
+		}
+		if sf.Type.Kind() == reflect.Struct {
+			f.Fields = structFields(sf.Type)
+		}
+		result = append(result, f)
+	}
+
+	return result
+}
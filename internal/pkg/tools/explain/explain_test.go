@@ -0,0 +1,73 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package explain_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/explain"
+)
+
+func TestDescribe_Lookup(t *testing.T) {
+	var f, err = explain.Describe(reflect.TypeOf(&registry.NetworkServiceEndpoint{}))
+	require.NoError(t, err)
+
+	labels, err := f.Lookup("networkServiceLabels", "labels")
+	require.NoError(t, err)
+	require.Equal(t, "map[string]string", labels.Type)
+
+	expires, err := f.Lookup("expiration_time")
+	require.NoError(t, err)
+	require.Equal(t, "expirationTime", expires.Name)
+	require.Equal(t, "timestamp", expires.Type)
+
+	_, err = f.Lookup("networkServiceLabels", "missing")
+	require.Error(t, err)
+}
+
+func TestPrint(t *testing.T) {
+	var f, err = explain.Describe(reflect.TypeOf(&networkservice.Connection{}))
+	require.NoError(t, err)
+
+	state, err := f.Lookup("state")
+	require.NoError(t, err)
+
+	var out strings.Builder
+	require.NoError(t, explain.Print(&out, "connection", []string{"state"}, state, false))
+	require.Equal(t, `KIND:   connection
+FIELD:  state <State>
+VALUES: UP, DOWN, REFRESH_REQUESTED
+`, out.String())
+
+	path, err := f.Lookup("path")
+	require.NoError(t, err)
+
+	out.Reset()
+	require.NoError(t, explain.Print(&out, "connection", []string{"path"}, path, true))
+	require.Contains(t, out.String(), `
+FIELDS:
+   index         <uint32>
+   pathSegments  <[]PathSegment>
+      name       <string>
+`)
+}
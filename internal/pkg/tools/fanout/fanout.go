@@ -0,0 +1,86 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fanout runs queries against several NSM domains at once
+package fanout
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+// Result is the result of the query for a domain
+type Result struct {
+	Domain    *domain.Domain
+	Resources []storage.Resource
+	Err       error
+}
+
+// Domains returns the domains to query: all the stored domains if all is set, otherwise the domains with the names
+func Domains(all bool, names []string) ([]*domain.Domain, error) {
+	if all && len(names) > 0 {
+		return nil, errors.New("--all-domains can not be used together with --domains")
+	}
+	if all {
+		var result, err = domain.List()
+		if err != nil {
+			return nil, err
+		}
+		if len(result) == 0 {
+			return nil, errors.New("no domains found, please use 'nsmctl create domain $DOMAIN_NAME'")
+		}
+		return result, nil
+	}
+
+	var result []*domain.Domain
+	var seen = make(map[string]struct{})
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		var d, err = domain.Load(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load domain %v", name)
+		}
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+// Run runs the query concurrently for each domain, the context of the query selects the domain.
+// Results are in the order of the domains.
+func Run(ctx context.Context, domains []*domain.Domain, query func(context.Context) ([]storage.Resource, error)) []*Result {
+	var result = make([]*Result, len(domains))
+	var wg sync.WaitGroup
+
+	for i, d := range domains {
+		result[i] = &Result{Domain: d}
+		wg.Add(1)
+		go func(r *Result) {
+			defer wg.Done()
+			r.Resources, r.Err = query(domain.WithContext(ctx, r.Domain))
+		}(result[i])
+	}
+
+	wg.Wait()
+	return result
+}
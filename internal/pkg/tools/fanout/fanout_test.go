@@ -0,0 +1,52 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fanout_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/fanout"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+func TestRun(t *testing.T) {
+	var domains = []*domain.Domain{domain.New("a"), domain.New("b"), domain.New("c")}
+
+	var results = fanout.Run(context.Background(), domains, func(ctx context.Context) ([]storage.Resource, error) {
+		var d, err = domain.FromContext(ctx)
+		require.NoError(t, err)
+		if d.Name == "b" {
+			return nil, errors.New("unavailable")
+		}
+		return []storage.Resource{&registry.NetworkService{Name: "ns-" + d.Name}}, nil
+	})
+
+	require.Len(t, results, 3)
+	for i, r := range results {
+		require.Equal(t, domains[i], r.Domain)
+	}
+	require.Equal(t, "ns-a", storage.NameOf(results[0].Resources[0]))
+	require.Error(t, results[1].Err)
+	require.Empty(t, results[1].Resources)
+	require.Equal(t, "ns-c", storage.NameOf(results[2].Resources[0]))
+}
@@ -0,0 +1,328 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonpath evaluates JSONPath templates such as '{.name}' or
+// '{range .networkServiceNames[*]}{@}{"\n"}{end}' against generic JSON values
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type node interface{}
+
+type textNode string
+
+type pathNode []segment
+
+type rangeNode struct {
+	path  pathNode
+	nodes []node
+}
+
+type segment struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// JSONPath is a parsed JSONPath template
+type JSONPath struct {
+	nodes []node
+}
+
+// Parse parses the template. Text without braces is treated as a single expression.
+func Parse(text string) (*JSONPath, error) {
+	if !strings.Contains(text, "{") {
+		text = "{" + text + "}"
+	}
+
+	var root = &rangeNode{}
+	var stack = []*rangeNode{root}
+
+	for len(text) > 0 {
+		var top = stack[len(stack)-1]
+		var open = strings.Index(text, "{")
+		if open < 0 {
+			top.nodes = append(top.nodes, textNode(text))
+			break
+		}
+		if open > 0 {
+			top.nodes = append(top.nodes, textNode(text[:open]))
+		}
+		var closing = findClosingBrace(text, open+1)
+		if closing < 0 {
+			return nil, errors.Errorf("unclosed action in %q", text)
+		}
+		var action = strings.TrimSpace(text[open+1 : closing])
+		text = text[closing+1:]
+
+		switch {
+		case action == "end":
+			if len(stack) == 1 {
+				return nil, errors.New("unexpected {end}")
+			}
+			stack = stack[:len(stack)-1]
+		case strings.HasPrefix(action, "range "):
+			var path, err = parsePath(strings.TrimPrefix(action, "range "))
+			if err != nil {
+				return nil, err
+			}
+			var r = &rangeNode{path: path}
+			top.nodes = append(top.nodes, r)
+			stack = append(stack, r)
+		case strings.HasPrefix(action, `"`):
+			var s, err = strconv.Unquote(action)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid string literal %v", action)
+			}
+			top.nodes = append(top.nodes, textNode(s))
+		default:
+			var path, err = parsePath(action)
+			if err != nil {
+				return nil, err
+			}
+			top.nodes = append(top.nodes, path)
+		}
+	}
+
+	if len(stack) != 1 {
+		return nil, errors.New("{range} is not closed with {end}")
+	}
+
+	return &JSONPath{nodes: root.nodes}, nil
+}
+
+// Path is a parsed path expression, e.g. .networkServiceNames[0]
+type Path struct {
+	path pathNode
+}
+
+// ParsePath parses the path expression. Braces and the leading dot are optional, e.g. {.name}, .name and name are the same.
+func ParsePath(text string) (*Path, error) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "{") && strings.HasSuffix(text, "}") {
+		text = strings.TrimSpace(text[1 : len(text)-1])
+	}
+	if text != "" && !strings.ContainsAny(text[:1], ".[$@") {
+		text = "." + text
+	}
+	var path, err = parsePath(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Path{path: path}, nil
+}
+
+// Find returns the values at the path
+func (p *Path) Find(data any) []any {
+	return p.path.eval(data)
+}
+
+// Format returns the text representation of the value as it is printed by templates
+func Format(v any) (string, error) {
+	return format(v)
+}
+
+// Execute writes the result of the template applied to the data
+func (j *JSONPath) Execute(w io.Writer, data any) error {
+	return execute(w, j.nodes, data)
+}
+
+func execute(w io.Writer, nodes []node, data any) error {
+	for _, n := range nodes {
+		switch v := n.(type) {
+		case textNode:
+			if _, err := io.WriteString(w, string(v)); err != nil {
+				return err
+			}
+		case pathNode:
+			var results = v.eval(data)
+			var printed = make([]string, 0, len(results))
+			for _, r := range results {
+				var s, err = format(r)
+				if err != nil {
+					return err
+				}
+				printed = append(printed, s)
+			}
+			if _, err := io.WriteString(w, strings.Join(printed, " ")); err != nil {
+				return err
+			}
+		case *rangeNode:
+			for _, r := range v.path.eval(data) {
+				var items = []any{r}
+				if list, ok := r.([]any); ok {
+					items = list
+				}
+				for _, item := range items {
+					if err := execute(w, v.nodes, item); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func findClosingBrace(text string, from int) int {
+	var quoted bool
+	for i := from; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted = !quoted
+		case '}':
+			if !quoted {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parsePath(text string) (pathNode, error) {
+	var result pathNode
+	var original = text
+
+	text = strings.TrimSpace(text)
+	text = strings.TrimPrefix(text, "$")
+	text = strings.TrimPrefix(text, "@")
+
+	for len(text) > 0 {
+		switch text[0] {
+		case '.':
+			text = text[1:]
+			if strings.HasPrefix(text, ".") {
+				return nil, errors.Errorf("recursive descent is not supported: %v", original)
+			}
+			var end = strings.IndexAny(text, ".[")
+			if end < 0 {
+				end = len(text)
+			}
+			var field = text[:end]
+			text = text[end:]
+			if field == "" {
+				continue
+			}
+			if field == "*" {
+				result = append(result, segment{wildcard: true})
+				continue
+			}
+			result = append(result, segment{field: field})
+		case '[':
+			var end = strings.Index(text, "]")
+			if end < 0 {
+				return nil, errors.Errorf("unclosed bracket in %v", original)
+			}
+			var inner = strings.TrimSpace(text[1:end])
+			text = text[end+1:]
+			switch {
+			case inner == "*":
+				result = append(result, segment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				result = append(result, segment{field: inner[1 : len(inner)-1]})
+			default:
+				var index, err = strconv.Atoi(inner)
+				if err != nil {
+					return nil, errors.Errorf("invalid index %q in %v", inner, original)
+				}
+				result = append(result, segment{index: index, isIndex: true})
+			}
+		default:
+			return nil, errors.Errorf("unexpected %q in %v", text[0], original)
+		}
+	}
+
+	return result, nil
+}
+
+func (p pathNode) eval(data any) []any {
+	var current = []any{data}
+
+	for _, s := range p {
+		var next []any
+		for _, v := range current {
+			next = append(next, s.apply(v)...)
+		}
+		current = next
+	}
+
+	return current
+}
+
+func (s segment) apply(v any) []any {
+	switch typed := v.(type) {
+	case map[string]any:
+		if s.wildcard {
+			var keys = make([]string, 0, len(typed))
+			for k := range typed {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			var result = make([]any, 0, len(keys))
+			for _, k := range keys {
+				result = append(result, typed[k])
+			}
+			return result
+		}
+		if item, ok := typed[s.field]; ok && !s.isIndex {
+			return []any{item}
+		}
+	case []any:
+		if s.wildcard {
+			return typed
+		}
+		if s.isIndex {
+			var index = s.index
+			if index < 0 {
+				index += len(typed)
+			}
+			if index >= 0 && index < len(typed) {
+				return []any{typed[index]}
+			}
+		}
+	}
+	return nil
+}
+
+func format(v any) (string, error) {
+	switch typed := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return typed, nil
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	case map[string]any, []any:
+		var b, err = json.Marshal(typed)
+		return string(b), err
+	default:
+		return fmt.Sprint(typed), nil
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonpath_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/jsonpath"
+)
+
+const sample = `{
+	"name": "nse-1",
+	"networkServiceNames": ["a", "b"],
+	"networkServiceLabels": {"a": {"labels": {"app": "x"}}},
+	"url": "tcp://10.0.0.1:5001"
+}`
+
+func TestJSONPath_Execute(t *testing.T) {
+	var data any
+	require.NoError(t, json.Unmarshal([]byte(sample), &data))
+
+	for template, expected := range map[string]string{
+		"{.name}":                                   "nse-1",
+		".name":                                     "nse-1",
+		"{$.url}":                                   "tcp://10.0.0.1:5001",
+		"{.networkServiceNames[1]}":                 "b",
+		"{.networkServiceNames[-1]}":                "b",
+		"{.networkServiceNames[*]}":                 "a b",
+		"{.networkServiceNames}":                    `["a","b"]`,
+		"{.networkServiceLabels.a.labels.app}":      "x",
+		"{.networkServiceLabels['a'].labels}":       `{"app":"x"}`,
+		"{.missing}":                                "",
+		"name={.name}{\"\\n\"}":                     "name=nse-1\n",
+		"{range .networkServiceNames[*]}[{@}]{end}": "[a][b]",
+		"{range .networkServiceNames}{@},{end}":     "a,b,",
+	} {
+		var p, err = jsonpath.Parse(template)
+		require.NoError(t, err, template)
+
+		var out strings.Builder
+		require.NoError(t, p.Execute(&out, data), template)
+		require.Equal(t, expected, out.String(), template)
+	}
+}
+
+func TestJSONPath_ParseErrors(t *testing.T) {
+	for _, template := range []string{
+		"{.name",
+		"{range .items[*]}{.name}",
+		"{end}",
+		"{.items[x]}",
+		"{..name}",
+	} {
+		var _, err = jsonpath.Parse(template)
+		require.Error(t, err, template)
+	}
+}
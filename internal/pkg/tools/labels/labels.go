@@ -0,0 +1,222 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package labels implements Kubernetes-style label selectors such as 'app=nse,version!=v1,env in (dev,prod)'
+package labels
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Operator is an operator of the requirement
+type Operator string
+
+const (
+	// Equals requires the label to have the value
+	Equals Operator = "="
+	// NotEquals requires the label to be missed or to have another value
+	NotEquals Operator = "!="
+	// In requires the label to have one of the values
+	In Operator = "in"
+	// NotIn requires the label to be missed or to have none of the values
+	NotIn Operator = "notin"
+	// Exists requires the label to be present
+	Exists Operator = "exists"
+	// DoesNotExist requires the label to be missed
+	DoesNotExist Operator = "!"
+)
+
+var (
+	keyPattern = `[A-Za-z0-9]([-A-Za-z0-9_./]*[A-Za-z0-9])?`
+	setPattern = regexp.MustCompile(`^(` + keyPattern + `)\s+(in|notin)\s+\(([^()]*)\)$`)
+	keyRegexp  = regexp.MustCompile(`^` + keyPattern + `$`)
+)
+
+// Requirement is a single condition of the selector
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Matches returns true if the labels satisfy the requirement
+func (r *Requirement) Matches(labels map[string]string) bool {
+	var value, ok = labels[r.Key]
+
+	switch r.Operator {
+	case Equals, In:
+		return ok && contains(r.Values, value)
+	case NotEquals, NotIn:
+		return !ok || !contains(r.Values, value)
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	}
+
+	return false
+}
+
+func (r *Requirement) String() string {
+	switch r.Operator {
+	case Exists:
+		return r.Key
+	case DoesNotExist:
+		return "!" + r.Key
+	case In, NotIn:
+		return r.Key + " " + string(r.Operator) + " (" + strings.Join(r.Values, ",") + ")"
+	default:
+		return r.Key + string(r.Operator) + r.Values[0]
+	}
+}
+
+// Selector is a set of requirements that all must be satisfied
+type Selector []*Requirement
+
+// Parse parses the selector. Supported forms: 'key=value', 'key==value', 'key!=value',
+// 'key in (v1,v2)', 'key notin (v1,v2)', 'key' and '!key'. Requirements are separated by commas.
+func Parse(s string) (Selector, error) {
+	var result Selector
+
+	for _, term := range splitTerms(s) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		var r, err = parseRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+// Empty returns true if the selector has no requirements and so matches everything
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+// Matches returns true if the labels satisfy all the requirements
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equalities returns labels that must have exact values, e.g. 'app=nse' or 'app in (nse)'
+func (s Selector) Equalities() map[string]string {
+	var result = make(map[string]string)
+
+	for _, r := range s {
+		if (r.Operator == Equals || r.Operator == In) && len(r.Values) == 1 {
+			result[r.Key] = r.Values[0]
+		}
+	}
+
+	return result
+}
+
+func (s Selector) String() string {
+	var terms = make([]string, 0, len(s))
+	for _, r := range s {
+		terms = append(terms, r.String())
+	}
+	return strings.Join(terms, ",")
+}
+
+func parseRequirement(term string) (*Requirement, error) {
+	if m := setPattern.FindStringSubmatch(term); m != nil {
+		var values []string
+		for _, v := range strings.Split(m[4], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return nil, errors.Errorf("no values in %q", term)
+		}
+		sort.Strings(values)
+		return &Requirement{Key: m[1], Operator: Operator(m[3]), Values: values}, nil
+	}
+
+	for _, op := range []string{"!=", "==", "="} {
+		if i := strings.Index(term, op); i >= 0 {
+			var key, value = strings.TrimSpace(term[:i]), strings.TrimSpace(term[i+len(op):])
+			if !keyRegexp.MatchString(key) {
+				return nil, errors.Errorf("invalid label key in %q", term)
+			}
+			var operator = Equals
+			if op == "!=" {
+				operator = NotEquals
+			}
+			return &Requirement{Key: key, Operator: operator, Values: []string{value}}, nil
+		}
+	}
+
+	if strings.HasPrefix(term, "!") {
+		var key = strings.TrimSpace(term[1:])
+		if !keyRegexp.MatchString(key) {
+			return nil, errors.Errorf("invalid label key in %q", term)
+		}
+		return &Requirement{Key: key, Operator: DoesNotExist}, nil
+	}
+
+	if !keyRegexp.MatchString(term) {
+		return nil, errors.Errorf("invalid requirement %q", term)
+	}
+
+	return &Requirement{Key: term, Operator: Exists}, nil
+}
+
+// splitTerms splits the selector by commas that are not enclosed in parentheses
+func splitTerms(s string) []string {
+	var result []string
+	var depth, start int
+
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(result, s[start:])
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
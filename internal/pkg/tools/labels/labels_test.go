@@ -0,0 +1,76 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labels_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/labels"
+)
+
+func TestSelector_Matches(t *testing.T) {
+	var nseLabels = map[string]string{
+		"app":     "firewall",
+		"version": "v2",
+		"env":     "prod",
+	}
+
+	for selector, expected := range map[string]bool{
+		"":                                true,
+		"app=firewall":                    true,
+		"app==firewall":                   true,
+		"app=vpn":                         false,
+		"app!=vpn":                        true,
+		"missing!=vpn":                    true,
+		"app=firewall,version=v1":         false,
+		"env in (dev, prod)":              true,
+		"env in (dev,prod),app=firewall":  true,
+		"env notin (dev,prod)":            false,
+		"version notin (v1)":              true,
+		"app":                             true,
+		"!app":                            false,
+		"!missing":                        true,
+		"missing":                         false,
+		" app = firewall , env in (prod)": true,
+	} {
+		var s, err = labels.Parse(selector)
+		require.NoError(t, err, selector)
+		require.Equal(t, expected, s.Matches(nseLabels), selector)
+	}
+}
+
+func TestSelector_Equalities(t *testing.T) {
+	var s, err = labels.Parse("app=firewall,env in (prod),version in (v1,v2),zone!=a,tier")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"app": "firewall", "env": "prod"}, s.Equalities())
+	require.Equal(t, "app=firewall,env in (prod),version in (v1,v2),zone!=a,tier", s.String())
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, selector := range []string{
+		"=value",
+		"env in ()",
+		"env in (a",
+		"!",
+		"bad key",
+	} {
+		var _, err = labels.Parse(selector)
+		require.Error(t, err, selector)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package listing selects, sorts and pages lists of resources before they are printed
+package listing
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/codec"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/jsonpath"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+)
+
+// Options describes how to list resources
+type Options struct {
+	// SortBy is the path of the field to sort by, e.g. .expirationTime. Resources are sorted by name if empty.
+	SortBy string
+	// FieldSelector selects resources by the values of the fields, e.g. networkServiceNames=foo,url!=
+	FieldSelector string
+	// Limit is the maximum number of resources, no limit if zero
+	Limit int
+	// Offset is the number of resources to skip
+	Offset int
+}
+
+// Apply selects, sorts and pages the resources
+func Apply(list []storage.Resource, o *Options) ([]storage.Resource, error) {
+	if o.Limit < 0 || o.Offset < 0 {
+		return nil, errors.New("limit and offset can not be negative")
+	}
+
+	var selector, err = ParseFieldSelector(o.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*item
+	for _, r := range list {
+		var v any
+		if v, err = codec.ToPopulatedValue(r); err != nil {
+			return nil, err
+		}
+		if !selector.matches(v) {
+			continue
+		}
+		items = append(items, &item{resource: r, value: v, name: storage.NameOf(r)})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].name < items[j].name
+	})
+
+	if o.SortBy != "" {
+		var path *jsonpath.Path
+		if path, err = jsonpath.ParsePath(o.SortBy); err != nil {
+			return nil, err
+		}
+		for _, it := range items {
+			if values := path.Find(it.value); len(values) > 0 {
+				it.key = values[0]
+			}
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			return less(items[i].key, items[j].key)
+		})
+	}
+
+	if o.Offset >= len(items) {
+		return nil, nil
+	}
+	items = items[o.Offset:]
+	if o.Limit > 0 && o.Limit < len(items) {
+		items = items[:o.Limit]
+	}
+
+	var result = make([]storage.Resource, 0, len(items))
+	for _, it := range items {
+		result = append(result, it.resource)
+	}
+	return result, nil
+}
+
+type item struct {
+	resource storage.Resource
+	value    any
+	name     string
+	key      any
+}
+
+// less compares numbers by value and other values by their text, missing values go first
+func less(a, b any) bool {
+	switch {
+	case a == nil:
+		return b != nil
+	case b == nil:
+		return false
+	}
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			return x < y
+		}
+	}
+	var x, _ = jsonpath.Format(a)
+	var y, _ = jsonpath.Format(b)
+	return x < y
+}
+
+// FieldSelector selects resources by the values of their fields
+type FieldSelector []*fieldRequirement
+
+type fieldRequirement struct {
+	path     *jsonpath.Path
+	value    string
+	negative bool
+}
+
+// ParseFieldSelector parses the comma separated list of requirements 'path=value', 'path==value' and 'path!=value'.
+// Lists match if any of their values matches, a missing field has the empty value.
+func ParseFieldSelector(text string) (FieldSelector, error) {
+	var result FieldSelector
+
+	for _, term := range strings.Split(text, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		var r = new(fieldRequirement)
+		var key, value string
+		var ok bool
+
+		if key, value, ok = strings.Cut(term, "!="); ok {
+			r.negative = true
+		} else if key, value, ok = strings.Cut(term, "=="); !ok {
+			if key, value, ok = strings.Cut(term, "="); !ok {
+				return nil, errors.Errorf("invalid field selector %q, expected path=value or path!=value", term)
+			}
+		}
+
+		var err error
+		if r.path, err = jsonpath.ParsePath(strings.TrimSpace(key)); err != nil {
+			return nil, err
+		}
+		r.value = strings.TrimSpace(value)
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+// Matches returns true if the resource matches all the requirements
+func (s FieldSelector) Matches(r storage.Resource) bool {
+	if len(s) == 0 {
+		return true
+	}
+	var v, err = codec.ToPopulatedValue(r)
+	if err != nil {
+		return false
+	}
+	return s.matches(v)
+}
+
+func (s FieldSelector) matches(v any) bool {
+	for _, r := range s {
+		if r.matches(v) == r.negative {
+			return false
+		}
+	}
+	return true
+}
+
+// matches returns true if any of the values at the path is equal to the value of the requirement
+func (r *fieldRequirement) matches(v any) bool {
+	var values []any
+	for _, found := range r.path.Find(v) {
+		if list, ok := found.([]any); ok {
+			values = append(values, list...)
+			continue
+		}
+		values = append(values, found)
+	}
+	if len(values) == 0 {
+		values = []any{nil}
+	}
+	for _, value := range values {
+		if text, err := jsonpath.Format(value); err == nil && text == r.value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listing_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/listing"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+func endpoints() []storage.Resource {
+	return []storage.Resource{
+		&registry.NetworkServiceEndpoint{Name: "nse-c", Url: "tcp://10.0.0.3:5001", NetworkServiceNames: []string{"ns-1"}},
+		&registry.NetworkServiceEndpoint{Name: "nse-a", NetworkServiceNames: []string{"ns-1", "ns-2"}},
+		&registry.NetworkServiceEndpoint{Name: "nse-b", Url: "tcp://10.0.0.1:5001", NetworkServiceNames: []string{"ns-2"}},
+	}
+}
+
+func names(list []storage.Resource) []string {
+	var result []string
+	for _, r := range list {
+		result = append(result, storage.NameOf(r))
+	}
+	return result
+}
+
+func TestApply_SortsByName(t *testing.T) {
+	var list, err = listing.Apply(endpoints(), &listing.Options{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"nse-a", "nse-b", "nse-c"}, names(list))
+}
+
+func TestApply_SortBy(t *testing.T) {
+	var list, err = listing.Apply(endpoints(), &listing.Options{SortBy: ".url"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"nse-a", "nse-b", "nse-c"}, names(list))
+
+	list, err = listing.Apply(endpoints(), &listing.Options{SortBy: "{.networkServiceNames[0]}"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"nse-a", "nse-c", "nse-b"}, names(list))
+}
+
+func TestApply_FieldSelector(t *testing.T) {
+	var list, err = listing.Apply(endpoints(), &listing.Options{FieldSelector: "networkServiceNames=ns-2"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"nse-a", "nse-b"}, names(list))
+
+	list, err = listing.Apply(endpoints(), &listing.Options{FieldSelector: "networkServiceNames=ns-2,url!="})
+	require.NoError(t, err)
+	require.Equal(t, []string{"nse-b"}, names(list))
+
+	list, err = listing.Apply(endpoints(), &listing.Options{FieldSelector: "networkServiceNames!=ns-1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"nse-b"}, names(list))
+
+	_, err = listing.Apply(endpoints(), &listing.Options{FieldSelector: "url"})
+	require.Error(t, err)
+}
+
+func TestApply_LimitOffset(t *testing.T) {
+	var list, err = listing.Apply(endpoints(), &listing.Options{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"nse-b"}, names(list))
+
+	list, err = listing.Apply(endpoints(), &listing.Options{Offset: 5})
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	_, err = listing.Apply(endpoints(), &listing.Options{Limit: -1})
+	require.Error(t, err)
+}
+
+func TestApply_Domains(t *testing.T) {
+	var domains = []storage.Resource{
+		&domain.Domain{Name: "b", RegistryService: "registry.a", IsInsecure: true},
+		&domain.Domain{Name: "a", RegistryService: "registry.b"},
+	}
+
+	var list, err = listing.Apply(domains, &listing.Options{SortBy: ".registryService"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "a"}, names(list))
+
+	list, err = listing.Apply(domains, &listing.Options{FieldSelector: "name=a"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, names(list))
+
+	list, err = listing.Apply(domains, &listing.Options{FieldSelector: "isInsecure=true"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, names(list))
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package manifest reads NSM resources from YAML and JSON manifests.
+// A manifest may contain several documents separated by '---', the kind of each document is set by the 'kind' field.
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Stdin is the path that means the standard input
+const Stdin = "-"
+
+// Document is a single resource of the manifest
+type Document struct {
+	// Source points to the document, e.g. ns.yaml#1
+	Source string
+	// Kind is the value of the 'kind' field, empty if the document has no kind
+	Kind string
+	// APIVersion is the value of the 'apiVersion' field of Kubernetes manifests
+	APIVersion string
+	// Data is the document without the 'kind' and 'apiVersion' fields
+	Data []byte
+}
+
+// Read reads documents from the files. Directories are read recursively, patterns are expanded
+// and Stdin is read from the stdin.
+func Read(paths []string, stdin io.Reader) ([]*Document, error) {
+	var result []*Document
+
+	for _, p := range paths {
+		if p == Stdin {
+			docs, err := Parse("<stdin>", stdin)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, docs...)
+			continue
+		}
+
+		files, err := expand(p)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			docs, err := readFile(file)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, docs...)
+		}
+	}
+
+	return result, nil
+}
+
+// Parse splits the manifest into the documents. Empty documents are skipped.
+func Parse(source string, r io.Reader) ([]*Document, error) {
+	var result []*Document
+	var index int
+
+	var parse = func(b []byte) error {
+		index++
+		var doc yaml.MapSlice
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return errors.Wrapf(err, "%v#%v: failed to parse", source, index)
+		}
+		if len(doc) == 0 {
+			return nil
+		}
+
+		var d = &Document{Source: source + "#" + strconv.Itoa(index)}
+		var fields = yaml.MapSlice{}
+		for _, item := range doc {
+			var target *string
+			switch item.Key {
+			case "kind":
+				target = &d.Kind
+			case "apiVersion":
+				target = &d.APIVersion
+			default:
+				fields = append(fields, item)
+				continue
+			}
+			value, ok := item.Value.(string)
+			if !ok {
+				return errors.Errorf("%v: %v must be a string", d.Source, item.Key)
+			}
+			*target = value
+		}
+
+		var err error
+		if d.Data, err = yaml.Marshal(fields); err != nil {
+			return errors.Wrapf(err, "%v: failed to encode", d.Source)
+		}
+
+		result = append(result, d)
+		return nil
+	}
+
+	var buf bytes.Buffer
+	var scanner = bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+
+	for scanner.Scan() {
+		if isSeparator(scanner.Text()) {
+			if err := parse(buf.Bytes()); err != nil {
+				return nil, err
+			}
+			buf.Reset()
+			continue
+		}
+		buf.Write(scanner.Bytes())
+		buf.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %v", source)
+	}
+	if err := parse(buf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func isSeparator(line string) bool {
+	return strings.TrimRight(line, " \t\r") == "---"
+}
+
+func readFile(path string) ([]*Document, error) {
+	// #nosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %v", path)
+	}
+	defer func() { _ = f.Close() }()
+
+	return Parse(path, f)
+}
+
+// expand returns the manifest files of the path. The path can be a file, a directory or a glob pattern.
+func expand(path string) ([]string, error) {
+	var matches = []string{path}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		matches, err = filepath.Glob(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "bad pattern %v", path)
+		}
+		if len(matches) == 0 {
+			return nil, errors.Errorf("%v: no such file or directory", path)
+		}
+	}
+
+	var result []string
+
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if !info.IsDir() {
+			result = append(result, m)
+			continue
+		}
+		err = filepath.Walk(m, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && isManifest(p) {
+				result = append(result, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %v", m)
+		}
+	}
+
+	return result, nil
+}
+
+func isManifest(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/manifest"
+)
+
+const twoKinds = `# services
+kind: NetworkService
+name: ns-1
+---
+---
+apiVersion: networkservicemesh.io/v1
+kind: NetworkServiceEndpoint
+metadata:
+  name: nse-1
+`
+
+func TestParse(t *testing.T) {
+	var docs, err = manifest.Parse("test.yaml", strings.NewReader(twoKinds))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	require.Equal(t, "test.yaml#1", docs[0].Source)
+	require.Equal(t, "NetworkService", docs[0].Kind)
+	require.Equal(t, "name: ns-1\n", string(docs[0].Data))
+
+	require.Equal(t, "test.yaml#3", docs[1].Source)
+	require.Equal(t, "NetworkServiceEndpoint", docs[1].Kind)
+	require.Equal(t, "networkservicemesh.io/v1", docs[1].APIVersion)
+	require.Equal(t, "metadata:\n  name: nse-1\n", string(docs[1].Data))
+}
+
+func TestRead(t *testing.T) {
+	var dir = t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "nested"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("name: a"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nested", "b.yml"), []byte("name: b"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nested", "README.md"), []byte("# docs"), 0o600))
+
+	var docs, err = manifest.Read([]string{dir, manifest.Stdin}, strings.NewReader("name: c"))
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	require.Equal(t, "name: c\n", string(docs[2].Data))
+
+	docs, err = manifest.Read([]string{filepath.Join(dir, "*.yaml")}, nil)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "name: a\n", string(docs[0].Data))
+
+	_, err = manifest.Read([]string{filepath.Join(dir, "missing.yaml")}, nil)
+	require.Error(t, err)
+}
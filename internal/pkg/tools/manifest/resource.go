@@ -0,0 +1,85 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/codec"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/crd"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+)
+
+// Resource decodes the document into a new resource of the storage selected by the kind of the document.
+// Documents without kind are decoded into the resource of s.
+func (d *Document) Resource(ctx context.Context, storages map[string]*storage.Storage, s *storage.Storage) (*storage.Storage, storage.Resource, error) {
+	var err error
+
+	if s, err = storageOf(storages, s, d); err != nil {
+		return nil, nil, err
+	}
+	if err = s.Check(storage.VerbCreate); err != nil {
+		return nil, nil, err
+	}
+
+	var result = s.Create(ctx)
+	var data = d.Data
+
+	if d.APIVersion != "" {
+		if data, err = crd.Unmarshal(d.APIVersion, d.Kind, data); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(data) > 0 {
+		if err = codec.UnmarshalYAML(data, result); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return s, result, nil
+}
+
+// storageOf finds the storage for the document by its kind, s is used for the documents without kind
+func storageOf(storages map[string]*storage.Storage, s *storage.Storage, doc *Document) (*storage.Storage, error) {
+	if doc.Kind == "" {
+		if s == nil {
+			return nil, errors.New("kind is required")
+		}
+		return s, nil
+	}
+
+	var result, ok = storages[strings.ToLower(doc.Kind)]
+	if !ok {
+		for _, item := range storages {
+			if strings.EqualFold(item.Kind, doc.Kind) {
+				result, ok = item, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil, errors.New("unknown kind " + doc.Kind)
+	}
+	if s != nil && s != result {
+		return nil, errors.New("kind " + doc.Kind + " doesn't match type " + s.Kind)
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package patch applies patches to the generic form of resources produced by encoding/json
+package patch
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Type is a kind of the patch
+type Type string
+
+const (
+	// Merge is JSON merge patch (RFC 7386): objects are merged, nulls delete fields, other values replace the original
+	Merge Type = "merge"
+	// Strategic works as Merge, but lists of scalars are merged as sets, e.g. network service names
+	Strategic Type = "strategic"
+)
+
+// Types lists all known types of patches
+var Types = []Type{Merge, Strategic}
+
+// Apply applies the patch of the type to the original value
+func Apply(t Type, original, patch any) (any, error) {
+	switch t {
+	case Merge:
+		return merge(original, patch, false), nil
+	case Strategic:
+		return merge(original, patch, true), nil
+	}
+	return nil, errors.Errorf("unknown patch type %q, expected one of %v", t, Types)
+}
+
+func merge(original, patch any, strategic bool) any {
+	switch p := patch.(type) {
+	case map[string]any:
+		var o, ok = original.(map[string]any)
+		var result = make(map[string]any)
+		if ok {
+			for k, v := range o {
+				result[k] = v
+			}
+		}
+		for k, v := range p {
+			if v == nil {
+				delete(result, k)
+				continue
+			}
+			result[k] = merge(result[k], v, strategic)
+		}
+		return result
+	case []any:
+		var o, ok = original.([]any)
+		if !strategic || !ok || !isScalars(o) || !isScalars(p) {
+			return p
+		}
+		var result = append([]any{}, o...)
+		for _, v := range p {
+			if !containsValue(result, v) {
+				result = append(result, v)
+			}
+		}
+		return result
+	default:
+		return patch
+	}
+}
+
+func isScalars(list []any) bool {
+	for _, v := range list {
+		switch v.(type) {
+		case map[string]any, []any:
+			return false
+		}
+	}
+	return true
+}
+
+func containsValue(list []any, v any) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package patch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/patch"
+)
+
+const nse = `{
+	"name": "nse-1",
+	"url": "tcp://10.0.0.1:5001",
+	"networkServiceNames": ["ns-1"],
+	"networkServiceLabels": {"ns-1": {"labels": {"app": "a", "env": "dev"}}}
+}`
+
+func apply(t *testing.T, patchType patch.Type, original, p string) string {
+	var o, v any
+	require.NoError(t, json.Unmarshal([]byte(original), &o))
+	require.NoError(t, json.Unmarshal([]byte(p), &v))
+
+	var result, err = patch.Apply(patchType, o, v)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(result)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestApply_Merge(t *testing.T) {
+	require.JSONEq(t, `{
+		"name": "nse-1",
+		"url": "tcp://10.0.0.1:5001",
+		"networkServiceNames": ["ns-2"],
+		"networkServiceLabels": {"ns-1": {"labels": {"app": "b"}}}
+	}`, apply(t, patch.Merge, nse, `{
+		"networkServiceNames": ["ns-2"],
+		"networkServiceLabels": {"ns-1": {"labels": {"app": "b", "env": null}}}
+	}`))
+}
+
+func TestApply_Strategic(t *testing.T) {
+	require.JSONEq(t, `{
+		"name": "nse-1",
+		"url": "tcp://10.0.0.1:5001",
+		"networkServiceNames": ["ns-1", "ns-2"],
+		"networkServiceLabels": {"ns-1": {"labels": {"app": "a", "env": "dev"}}, "ns-2": {"labels": {"app": "c"}}}
+	}`, apply(t, patch.Strategic, nse, `{
+		"networkServiceNames": ["ns-2", "ns-1"],
+		"networkServiceLabels": {"ns-2": {"labels": {"app": "c"}}}
+	}`))
+}
+
+func TestApply_UnknownType(t *testing.T) {
+	var _, err = patch.Apply("json", nil, nil)
+	require.Error(t, err)
+}
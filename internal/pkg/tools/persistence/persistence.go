@@ -14,86 +14,235 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package persistence allows to stora custom data for nsmctl in user cache
+// Package persistence stores custom data of nsmctl in a versioned configuration file.
+// The file is readable by the user only, it is replaced atomically and updates are serialized by a file lock.
 package persistence
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 	"gopkg.in/yaml.v2"
 
 	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
 )
 
-// PathOf finds path for the resource in the nsmctl cache
-func PathOf[T any](key string) string {
-	var zero = new(T)
-	if d, err := os.UserCacheDir(); err != nil {
+// Version is the version of the schema of the configuration file
+const Version = 1
+
+// ConfigEnv is the environment variable that overrides the path of the configuration file
+const ConfigEnv = "NSMCTL_CONFIG"
+
+var configPath string
+
+// document is the content of the configuration file
+type document struct {
+	Version int `yaml:"version"`
+	// Current maps kinds of the resources to the keys of the current ones, e.g. the current context
+	Current map[string]string `yaml:"current,omitempty"`
+	// Resources maps kinds of the resources to the resources by their keys
+	Resources map[string]map[string]any `yaml:"resources,omitempty"`
+}
+
+// SetPath overrides the path of the configuration file, the default path is used if empty
+func SetPath(p string) {
+	configPath = p
+}
+
+// Path returns the path of the configuration file: the path set by SetPath, $NSMCTL_CONFIG or nsmctl/config.yaml
+// in the user configuration directory
+func Path() string {
+	if configPath != "" {
+		return configPath
+	}
+	if p := os.Getenv(ConfigEnv); p != "" {
+		return p
+	}
+	return defaultPath()
+}
+
+// defaultPath returns nsmctl/config.yaml in the user configuration directory
+func defaultPath() string {
+	if d, err := os.UserConfigDir(); err != nil {
 		panic(err.Error())
 	} else {
-		var t = fmt.Sprintf("%T", zero)
-		var pieces = strings.Split(t, ".")
-		t = pieces[len(pieces)-1]
-		t = strings.ToLower(t)
-		return filepath.Join(d, "nsmctl", t, key)
+		return filepath.Join(d, "nsmctl", "config.yaml")
 	}
 }
 
-// Delete deletes resource from the nsmctl cache
+// KindOf returns the name of the resource type in lower case, e.g. domain for *domain.Domain
+func KindOf[T any]() string {
+	var zero = new(T)
+	var t = fmt.Sprintf("%T", zero)
+	var pieces = strings.Split(t, ".")
+	t = pieces[len(pieces)-1]
+	return strings.ToLower(t)
+}
+
+// Delete deletes the resource
 func Delete[T any](key string) error {
-	var filePath = PathOf[T](key)
-	return os.Remove(filePath)
+	return Modify(func(items map[string]T) error {
+		if _, ok := items[key]; !ok {
+			return notExist[T](key)
+		}
+		delete(items, key)
+		return nil
+	})
 }
 
-// Store stores resource in the nsmctl cache
+// Store stores the resource
 func Store[T any](key string, value T) error {
-	var b []byte
-	var err error
+	return Modify(func(items map[string]T) error {
+		items[key] = value
+		return nil
+	})
+}
+
+// Load loads the resource, returns *NotExistError if there is no such resource
+func Load[T any](key string) (T, error) {
+	var result T
 
-	b, err = yaml.Marshal(value)
+	var doc, err = read()
 	if err != nil {
-		return err
+		return result, err
 	}
-	var filePath = PathOf[T](key)
 
-	if err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
-		return err
+	var v, ok = doc.Resources[KindOf[T]()][key]
+	if !ok {
+		return result, notExist[T](key)
 	}
-	return os.WriteFile(filePath, b, os.ModePerm)
+
+	err = convert(v, &result)
+	return result, err
 }
 
-// Load loads serializable resource from the nsmctl cache
-func Load[T any](key string) (T, error) {
-	var result T
-	var err error
-	var filePath = PathOf[T](key)
+// Current returns the key of the current resource of the type, empty if none is current
+func Current[T any]() (string, error) {
+	var doc, err = read()
+	if err != nil {
+		return "", err
+	}
+	return doc.Current[KindOf[T]()], nil
+}
 
-	// #nosec
-	if _, err = os.Stat(filePath); err != nil {
-		return result, err
+// View returns the configuration as YAML
+func View() ([]byte, error) {
+	var doc, err = read()
+	if err != nil {
+		return nil, err
 	}
+	return yaml.Marshal(doc)
+}
 
-	var b []byte
-	// #nosec
-	if b, err = os.ReadFile(filePath); err != nil {
-		return result, err
+// List loads all the resources of the type sorted by their keys
+func List[T any]() ([]T, error) {
+	var doc, err = read()
+	if err != nil {
+		return nil, err
 	}
 
-	err = yaml.Unmarshal(b, &result)
+	var items = doc.Resources[KindOf[T]()]
+	var keys []string
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-	return result, err
+	var result []T
+	for _, key := range keys {
+		var item T
+		if err = convert(items[key], &item); err != nil {
+			return nil, errors.Wrapf(err, "failed to load %v %v", KindOf[T](), key)
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// Modify passes all the resources of the type to the function and stores the result as a whole.
+// Concurrent modifications wait for each other, nothing is stored if the function fails.
+func Modify[T any](f func(items map[string]T) error) error {
+	return ModifyCurrent(func(items map[string]T, _ *string) error {
+		return f(items)
+	})
+}
+
+// ModifyCurrent is Modify that also passes the key of the current resource of the type, the key is empty if none is current.
+// The key is cleared if the function removes the current resource.
+func ModifyCurrent[T any](f func(items map[string]T, current *string) error) error {
+	var unlock, err = lock(Path() + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var doc *document
+	if doc, err = readFile(); os.IsNotExist(err) {
+		doc, err = migrate()
+	}
+	if err != nil {
+		return err
+	}
+
+	var kind = KindOf[T]()
+	var items = make(map[string]T)
+	for key, v := range doc.Resources[kind] {
+		var item T
+		if err = convert(v, &item); err != nil {
+			return errors.Wrapf(err, "failed to load %v %v", kind, key)
+		}
+		items[key] = item
+	}
+
+	var current = doc.Current[kind]
+	if err = f(items, &current); err != nil {
+		return err
+	}
+	if _, ok := items[current]; !ok {
+		current = ""
+	}
+	if doc.Current == nil {
+		doc.Current = make(map[string]string)
+	}
+	doc.Current[kind] = current
+	if current == "" {
+		delete(doc.Current, kind)
+	}
+
+	var values = make(map[string]any)
+	for key, item := range items {
+		var v any
+		if err = convert(item, &v); err != nil {
+			return err
+		}
+		values[key] = v
+	}
+	if doc.Resources == nil {
+		doc.Resources = make(map[string]map[string]any)
+	}
+	doc.Resources[kind] = values
+
+	return write(doc)
 }
 
 // Storage creates a storage abstraction for serializable resource
 func Storage[T storage.Resource]() *storage.Storage {
 	return &storage.Storage{
+		Kind: KindOf[T](),
+		Type: reflect.TypeOf(*new(T)),
 		Get: func(ctx context.Context, name string) (storage.Resource, error) {
-			return Load[T](name)
+			var result, err = Load[T](name)
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, &storage.NotFoundError{Kind: KindOf[T](), Name: name}
+			}
+			return result, err
 		},
 		Delete: func(ctx context.Context, name string) error {
 			return Delete[T](name)
@@ -102,19 +251,14 @@ func Storage[T storage.Resource]() *storage.Storage {
 			return Store(s, r.(T))
 		},
 		List: func(ctx context.Context) ([]storage.Resource, error) {
-			var files, err = os.ReadDir(PathOf[T](""))
+			var list, err = List[T]()
 			if err != nil {
 				return nil, err
 			}
 			var result []storage.Resource
-			for _, file := range files {
-				var resource, err = Load[T](file.Name())
-				if err != nil {
-					return nil, err
-				}
-				result = append(result, resource)
+			for _, item := range list {
+				result = append(result, item)
 			}
-
 			return result, nil
 		},
 		Create: func(ctx context.Context) storage.Resource {
@@ -128,3 +272,175 @@ func Storage[T storage.Resource]() *storage.Storage {
 		},
 	}
 }
+
+// NotExistError means that there is no resource with the key, it matches fs.ErrNotExist
+type NotExistError struct {
+	Kind string
+	Key  string
+}
+
+func (e *NotExistError) Error() string {
+	return fmt.Sprintf("%v %v is not found", e.Kind, e.Key)
+}
+
+// Is makes errors.Is(err, fs.ErrNotExist) true for the error
+func (e *NotExistError) Is(target error) bool {
+	return target == fs.ErrNotExist
+}
+
+func notExist[T any](key string) error {
+	return &NotExistError{Kind: KindOf[T](), Key: key}
+}
+
+// convert converts the value to the target through YAML
+func convert(v, target any) error {
+	var b, err = yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(b, target)
+}
+
+// read reads the configuration file, migrates the legacy cache if the file doesn't exist yet
+func read() (*document, error) {
+	var doc, err = readFile()
+	if !os.IsNotExist(err) {
+		return doc, err
+	}
+
+	var unlock func()
+	if unlock, err = lock(Path() + ".lock"); err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// Another invocation might have created the file while we were waiting for the lock
+	if doc, err = readFile(); !os.IsNotExist(err) {
+		return doc, err
+	}
+	return migrate()
+}
+
+func readFile() (*document, error) {
+	// #nosec
+	var b, err = os.ReadFile(Path())
+	if err != nil {
+		return nil, err
+	}
+
+	var doc = new(document)
+	if err = yaml.Unmarshal(b, doc); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %v", Path())
+	}
+	if doc.Version > Version {
+		return nil, errors.Errorf("%v has version %v, this nsmctl supports version %v and older, please update nsmctl", Path(), doc.Version, Version)
+	}
+	doc.Version = Version
+	return doc, nil
+}
+
+// write replaces the configuration file atomically
+func write(doc *document) error {
+	var b, err = yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	var p = Path()
+	if err = os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+
+	// CreateTemp creates the file with 0600 permissions
+	var f *os.File
+	if f, err = os.CreateTemp(filepath.Dir(p), filepath.Base(p)+".*.tmp"); err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if _, err = f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+// legacyPath returns the directory where older versions of nsmctl kept resources, a file per resource in a directory per kind
+func legacyPath() string {
+	var d, err = os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(d, "nsmctl")
+}
+
+// migrate creates the configuration file from the resources of the legacy cache and removes them.
+// Only the default configuration file is created from the cache, an overridden one starts empty and leaves the cache intact.
+// Returns an empty document if there is nothing to migrate. Must be called under the lock.
+func migrate() (*document, error) {
+	var doc = &document{Version: Version}
+	if Path() != defaultPath() {
+		return doc, nil
+	}
+
+	var dir = legacyPath()
+	var kinds, err = os.ReadDir(dir)
+	if dir == "" || os.IsNotExist(err) {
+		return doc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var migrated []string
+	for _, kind := range kinds {
+		if !kind.IsDir() {
+			continue
+		}
+		var files []os.DirEntry
+		if files, err = os.ReadDir(filepath.Join(dir, kind.Name())); err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			var p = filepath.Join(dir, kind.Name(), file.Name())
+			var b []byte
+			// #nosec
+			if b, err = os.ReadFile(p); err != nil {
+				return nil, err
+			}
+			var v any
+			if err = yaml.Unmarshal(b, &v); err != nil {
+				return nil, errors.Wrapf(err, "failed to migrate %v", p)
+			}
+			if doc.Resources == nil {
+				doc.Resources = make(map[string]map[string]any)
+			}
+			if doc.Resources[kind.Name()] == nil {
+				doc.Resources[kind.Name()] = make(map[string]any)
+			}
+			doc.Resources[kind.Name()][file.Name()] = v
+			migrated = append(migrated, p)
+		}
+	}
+
+	if len(migrated) == 0 {
+		return doc, nil
+	}
+	if err = write(doc); err != nil {
+		return nil, err
+	}
+	for _, p := range migrated {
+		_ = os.Remove(p)
+	}
+	return doc, nil
+}
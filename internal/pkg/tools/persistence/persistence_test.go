@@ -0,0 +1,168 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persistence_test
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/persistence"
+)
+
+type Item struct {
+	Name  string
+	Value int
+}
+
+func (i *Item) String() string {
+	return i.Name
+}
+
+func setup(t *testing.T) string {
+	var dir = t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv("HOME", dir)
+	t.Setenv(persistence.ConfigEnv, filepath.Join(dir, "config", "config.yaml"))
+	return dir
+}
+
+func TestStoreLoadDelete(t *testing.T) {
+	setup(t)
+
+	_, err := persistence.Load[*Item]("a")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	require.NoError(t, persistence.Store("b", &Item{Name: "b", Value: 2}))
+	require.NoError(t, persistence.Store("a", &Item{Name: "a", Value: 1}))
+
+	a, err := persistence.Load[*Item]("a")
+	require.NoError(t, err)
+	require.Equal(t, &Item{Name: "a", Value: 1}, a)
+
+	list, err := persistence.List[*Item]()
+	require.NoError(t, err)
+	require.Equal(t, []*Item{{Name: "a", Value: 1}, {Name: "b", Value: 2}}, list)
+
+	require.NoError(t, persistence.Delete[*Item]("a"))
+	_, err = persistence.Load[*Item]("a")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	require.ErrorIs(t, persistence.Delete[*Item]("a"), fs.ErrNotExist)
+}
+
+func TestModifyCurrent(t *testing.T) {
+	setup(t)
+
+	require.NoError(t, persistence.ModifyCurrent(func(items map[string]*Item, current *string) error {
+		require.Empty(t, *current)
+		items["a"] = &Item{Name: "a"}
+		*current = "a"
+		return nil
+	}))
+
+	current, err := persistence.Current[*Item]()
+	require.NoError(t, err)
+	require.Equal(t, "a", current)
+
+	require.NoError(t, persistence.Delete[*Item]("a"))
+	current, err = persistence.Current[*Item]()
+	require.NoError(t, err)
+	require.Empty(t, current)
+}
+
+func TestFile(t *testing.T) {
+	setup(t)
+
+	require.NoError(t, persistence.Store("a", &Item{Name: "a"}))
+
+	info, err := os.Stat(persistence.Path())
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	b, err := os.ReadFile(persistence.Path())
+	require.NoError(t, err)
+	require.Contains(t, string(b), "version: 1")
+
+	require.NoError(t, os.WriteFile(persistence.Path(), []byte("version: 100"), 0o600))
+	_, err = persistence.Load[*Item]("a")
+	require.Error(t, err)
+}
+
+func TestModify_Concurrent(t *testing.T) {
+	setup(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var name = fmt.Sprint("item-", i)
+			require.NoError(t, persistence.Store(name, &Item{Name: name}))
+		}(i)
+	}
+	wg.Wait()
+
+	list, err := persistence.List[*Item]()
+	require.NoError(t, err)
+	require.Len(t, list, 10)
+}
+
+func TestMigrate(t *testing.T) {
+	var dir = setup(t)
+	t.Setenv(persistence.ConfigEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+
+	var legacy = filepath.Join(dir, "cache", "nsmctl", "item")
+	require.NoError(t, os.MkdirAll(legacy, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "old"), []byte("name: old\nvalue: 7\n"), 0o600))
+
+	old, err := persistence.Load[*Item]("old")
+	require.NoError(t, err)
+	require.Equal(t, &Item{Name: "old", Value: 7}, old)
+
+	_, err = os.Stat(filepath.Join(legacy, "old"))
+	require.True(t, os.IsNotExist(err))
+
+	old, err = persistence.Load[*Item]("old")
+	require.NoError(t, err)
+	require.Equal(t, 7, old.Value)
+}
+
+func TestMigrate_OverriddenPath(t *testing.T) {
+	var dir = setup(t)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "default"))
+
+	var legacy = filepath.Join(dir, "cache", "nsmctl", "item")
+	require.NoError(t, os.MkdirAll(legacy, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "old"), []byte("name: old\nvalue: 7\n"), 0o600))
+
+	_, err := persistence.Load[*Item]("old")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	require.NoError(t, persistence.Store("new", &Item{Name: "new"}))
+	list, err := persistence.List[*Item]()
+	require.NoError(t, err)
+	require.Equal(t, []*Item{{Name: "new"}}, list)
+
+	_, err = os.Stat(filepath.Join(legacy, "old"))
+	require.NoError(t, err)
+}
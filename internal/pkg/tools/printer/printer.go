@@ -0,0 +1,208 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package printer contains printers that render NSM resources in machine readable formats
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/codec"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/crd"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/jsonpath"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+)
+
+// Formats lists output formats supported by New
+const Formats = "json|yaml|name|crd|jsonpath=TEMPLATE"
+
+// Printer prints resources
+type Printer interface {
+	Print([]any) error
+}
+
+// New creates a printer for the output format. Formats with arguments are passed as 'format=argument'.
+// The json and jsonpath printers print resources as a single list object unless the printer is for single resources,
+// e.g. a resource requested by the name or a watch event.
+func New(format string, out io.Writer, single bool) (Printer, error) {
+	var name, arg, _ = strings.Cut(format, "=")
+
+	switch name {
+	case "json":
+		return &jsonPrinter{out: out, single: single}, nil
+	case "yaml":
+		return &yamlPrinter{out: out}, nil
+	case "name":
+		return &namePrinter{out: out}, nil
+	case "crd":
+		return &crdPrinter{yaml: &yamlPrinter{out: out}}, nil
+	case "jsonpath":
+		if arg == "" {
+			return nil, errors.New("jsonpath template is required, e.g. -o jsonpath='{.name}'")
+		}
+		var p, err = jsonpath.Parse(arg)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathPrinter{out: out, path: p, single: single}, nil
+	}
+
+	return nil, errors.Errorf("unknown output format %q, expected one of %v", format, Formats)
+}
+
+// list is the object several resources are printed as, the same as the list of kubectl
+type list struct {
+	Kind  string            `json:"kind"`
+	Items []json.RawMessage `json:"items"`
+}
+
+func newList(items []any) (*list, error) {
+	var result = &list{Kind: "List", Items: make([]json.RawMessage, 0, len(items))}
+	for _, item := range items {
+		var b, err = codec.MarshalJSON(item)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, b)
+	}
+	return result, nil
+}
+
+type jsonPrinter struct {
+	out    io.Writer
+	single bool
+}
+
+func (p *jsonPrinter) Print(items []any) error {
+	if !p.single {
+		var l, err = newList(items)
+		if err != nil {
+			return err
+		}
+		return p.print(l)
+	}
+	for _, item := range items {
+		if err := p.print(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *jsonPrinter) print(v any) error {
+	var b, err = codec.MarshalJSON(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.out, string(b))
+	return err
+}
+
+type yamlPrinter struct {
+	out     io.Writer
+	printed bool
+}
+
+func (p *yamlPrinter) Print(items []any) error {
+	for _, item := range items {
+		var b, err = codec.MarshalYAML(item)
+		if err != nil {
+			return err
+		}
+		if p.printed {
+			if _, err = fmt.Fprintln(p.out, "---"); err != nil {
+				return err
+			}
+		}
+		if _, err = p.out.Write(b); err != nil {
+			return err
+		}
+		p.printed = true
+	}
+	return nil
+}
+
+// crdPrinter prints registry resources as custom resources of the Kubernetes registry
+type crdPrinter struct {
+	yaml *yamlPrinter
+}
+
+func (p *crdPrinter) Print(items []any) error {
+	for _, item := range items {
+		var cr, err = crd.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if err = p.yaml.Print([]any{cr}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type namePrinter struct {
+	out io.Writer
+}
+
+func (p *namePrinter) Print(items []any) error {
+	for _, item := range items {
+		var r, ok = item.(storage.Resource)
+		if !ok {
+			return errors.Errorf("%T is not a resource", item)
+		}
+		if _, err := fmt.Fprintln(p.out, storage.NameOf(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonPathPrinter struct {
+	out    io.Writer
+	path   *jsonpath.JSONPath
+	single bool
+}
+
+// Print runs the template against each resource if the printer is for single resources,
+// otherwise the template runs once against the list, e.g. '{.items[*].name}'
+func (p *jsonPathPrinter) Print(items []any) error {
+	if !p.single {
+		var l, err = newList(items)
+		if err != nil {
+			return err
+		}
+		return p.execute(l)
+	}
+	for _, item := range items {
+		if err := p.execute(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *jsonPathPrinter) execute(item any) error {
+	var v, err = codec.ToValue(item)
+	if err != nil {
+		return err
+	}
+	return p.path.Execute(p.out, v)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/printer"
+)
+
+func endpoints() []any {
+	return []any{
+		&registry.NetworkServiceEndpoint{Name: "nse-a", NetworkServiceNames: []string{"ns-1"}},
+		&registry.NetworkServiceEndpoint{Name: "nse-b", NetworkServiceNames: []string{"ns-2"}},
+	}
+}
+
+func TestJSON_List(t *testing.T) {
+	var out bytes.Buffer
+	var p, err = printer.New("json", &out, false)
+	require.NoError(t, err)
+	require.NoError(t, p.Print(endpoints()))
+	require.JSONEq(t, `{"kind":"List","items":[
+		{"name":"nse-a","networkServiceNames":["ns-1"]},
+		{"name":"nse-b","networkServiceNames":["ns-2"]}
+	]}`, out.String())
+
+	out.Reset()
+	require.NoError(t, p.Print(nil))
+	require.JSONEq(t, `{"kind":"List","items":[]}`, out.String())
+}
+
+func TestJSON_Single(t *testing.T) {
+	var out bytes.Buffer
+	var p, err = printer.New("json", &out, true)
+	require.NoError(t, err)
+	require.NoError(t, p.Print(endpoints()[:1]))
+	require.JSONEq(t, `{"name":"nse-a","networkServiceNames":["ns-1"]}`, out.String())
+}
+
+func TestJSONPath_List(t *testing.T) {
+	var out bytes.Buffer
+	var p, err = printer.New("jsonpath={.items[*].name}", &out, false)
+	require.NoError(t, err)
+	require.NoError(t, p.Print(endpoints()))
+	require.Equal(t, "nse-a nse-b", out.String())
+}
+
+func TestJSONPath_Single(t *testing.T) {
+	var out bytes.Buffer
+	var p, err = printer.New("jsonpath={.name}", &out, true)
+	require.NoError(t, err)
+	require.NoError(t, p.Print(endpoints()[:1]))
+	require.Equal(t, "nse-a", out.String())
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package reader reads resources for the commands, e.g. get, describe and delete: the flags of label selectors, lists
+// and domains, the resources selected by them and the output format of the current context
+package reader
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/fanout"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/listing"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+// Output returns the output format of the current context if the output flag is not set
+func Output(cmd *cobra.Command, output string) (string, error) {
+	if cmd.Flags().Changed("output") {
+		return output, nil
+	}
+	var c, err = domain.CurrentContext()
+	if err != nil || c == nil || c.Output == "" {
+		return output, err
+	}
+	return c.Output, nil
+}
+
+// AddQueryFlags adds the flags of the label selector
+func AddQueryFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("selector", "l", "", "label selector, supports '=', '==', '!=', 'in', 'notin' and existence checks, e.g. -l 'app=nse,env in (dev,prod)'")
+	cmd.Flags().StringP("network-service", "", "", "match labels of the network service only, lets the registry do the selection")
+}
+
+// Query returns the label query of the flags added by AddQueryFlags
+func Query(cmd *cobra.Command) (*storage.Query, error) {
+	var selector, err = cmd.Flags().GetString("selector")
+	if err != nil {
+		return nil, err
+	}
+	var networkService string
+	if networkService, err = cmd.Flags().GetString("network-service"); err != nil {
+		return nil, err
+	}
+	return storage.NewQuery(selector, networkService)
+}
+
+// AddListFlags adds the flags of sorting, selecting and paging of lists
+func AddListFlags(cmd *cobra.Command) {
+	cmd.Flags().String("sort-by", "", "path of the field to sort the list by, e.g. --sort-by=.expirationTime. The list is sorted by name if not set")
+	cmd.Flags().String("field-selector", "", "select resources by the values of the fields, supports '=', '==' and '!=', e.g. --field-selector=networkServiceNames=foo,url!=")
+	cmd.Flags().Int("limit", 0, "maximum number of resources in the list, no limit if 0")
+	cmd.Flags().Int("offset", 0, "number of resources to skip from the start of the list")
+}
+
+// ListOptions returns the options of the flags added by AddListFlags
+func ListOptions(cmd *cobra.Command) (*listing.Options, error) {
+	var opts = new(listing.Options)
+	var err error
+	if opts.SortBy, err = cmd.Flags().GetString("sort-by"); err != nil {
+		return nil, err
+	}
+	if opts.FieldSelector, err = cmd.Flags().GetString("field-selector"); err != nil {
+		return nil, err
+	}
+	if opts.Limit, err = cmd.Flags().GetInt("limit"); err != nil {
+		return nil, err
+	}
+	if opts.Offset, err = cmd.Flags().GetInt("offset"); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// CheckQuery checks that the label query can be used with the resources of the storage requested by the names
+func CheckQuery(q *storage.Query, resourceType string, s *storage.Storage, names []string) error {
+	if !q.Empty() && len(names) > 0 {
+		return errors.New("names can not be used together with a label selector")
+	}
+	if !q.Empty() && s.Labels == nil {
+		return errors.New(resourceType + " has no labels")
+	}
+	return nil
+}
+
+// Selection returns the label query and the list options of the flags added by AddQueryFlags and AddListFlags,
+// checks that they can be used with the resources of the storage requested by the names
+func Selection(cmd *cobra.Command, resourceType string, s *storage.Storage, names []string) (*storage.Query, *listing.Options, error) {
+	var q, err = Query(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = CheckQuery(q, resourceType, s, names); err != nil {
+		return nil, nil, err
+	}
+	var opts *listing.Options
+	if opts, err = ListOptions(cmd); err != nil {
+		return nil, nil, err
+	}
+	if opts.FieldSelector != "" && len(names) > 0 {
+		return nil, nil, errors.New("names can not be used together with a field selector")
+	}
+	return q, opts, nil
+}
+
+// AddDomainFlags adds the flags that select several domains to query
+func AddDomainFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("all-domains", false, "query all the domains concurrently")
+	cmd.Flags().StringSlice("domains", nil, "query the domains concurrently, e.g. --domains a,b")
+}
+
+// Domains returns the domains selected by --all-domains or --domains, nil if the current domain is used
+func Domains(cmd *cobra.Command) ([]*domain.Domain, error) {
+	var all, err = cmd.Flags().GetBool("all-domains")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	if names, err = cmd.Flags().GetStringSlice("domains"); err != nil {
+		return nil, err
+	}
+	if !all && len(names) == 0 {
+		return nil, nil
+	}
+	return fanout.Domains(all, names)
+}
+
+// Fetch returns the resources with the names or the list of the resources matching the query
+func Fetch(ctx context.Context, s *storage.Storage, names []string, q *storage.Query, opts *listing.Options) ([]storage.Resource, error) {
+	if len(names) > 0 {
+		return get(ctx, s, names)
+	}
+	var list, err = s.SelectQuery(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return listing.Apply(list, opts)
+}
+
+// FromDomains returns the resources of the domains with the names of their domains. The resources with the names
+// are returned in the order of the domains, lists of the domains are merged into one list and then selected,
+// sorted and paged as a whole, so --limit limits the merged list. Errors of the domains are written to errOut,
+// it fails only if all the domains fail.
+func FromDomains(ctx context.Context, errOut io.Writer, domains []*domain.Domain, s *storage.Storage, names []string, q *storage.Query, opts *listing.Options) ([]storage.Resource, map[storage.Resource]string, error) {
+	if len(domains) == 0 {
+		return nil, nil, errors.New("no domains to query")
+	}
+
+	var results = fanout.Run(ctx, domains, func(ctx context.Context) ([]storage.Resource, error) {
+		if len(names) > 0 {
+			return get(ctx, s, names)
+		}
+		return s.SelectQuery(ctx, q)
+	})
+
+	var items []storage.Resource
+	var domainOf = make(map[storage.Resource]string)
+	var failed int
+
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			_, _ = fmt.Fprintf(errOut, "domain %v: %v\n", r.Domain.Name, r.Err)
+			continue
+		}
+		for _, item := range r.Resources {
+			domainOf[item] = r.Domain.Name
+			items = append(items, item)
+		}
+	}
+
+	if failed == len(results) {
+		return nil, nil, errors.New("all the domains failed")
+	}
+
+	if len(names) > 0 {
+		return items, domainOf, nil
+	}
+	var list, err = listing.Apply(items, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return list, domainOf, nil
+}
+
+// get returns the resources with the names
+func get(ctx context.Context, s *storage.Storage, names []string) ([]storage.Resource, error) {
+	if err := s.Check(storage.VerbGet); err != nil {
+		return nil, err
+	}
+	var result []storage.Resource
+	for _, name := range names {
+		var v, err = s.Get(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reader_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/listing"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/reader"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/storage"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+// services returns a storage with network services ns-1, ns-2, ns-3 in each domain, the domain "failed" fails
+func services() *storage.Storage {
+	var list = func(ctx context.Context) ([]storage.Resource, error) {
+		var d, err = domain.FromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if d.Name == "failed" {
+			return nil, errors.New("unavailable")
+		}
+		var result []storage.Resource
+		for _, n := range []string{"ns-3", "ns-1", "ns-2"} {
+			result = append(result, &registry.NetworkService{Name: n, Payload: d.Name})
+		}
+		return result, nil
+	}
+	return &storage.Storage{
+		Kind: "networkservice",
+		List: list,
+		Get: func(ctx context.Context, name string) (storage.Resource, error) {
+			var all, err = list(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range all {
+				if storage.NameOf(r) == name {
+					return r, nil
+				}
+			}
+			return nil, &storage.NotFoundError{Kind: "networkservice", Name: name}
+		},
+	}
+}
+
+func names(list []storage.Resource, domainOf map[storage.Resource]string) []string {
+	var result []string
+	for _, r := range list {
+		result = append(result, domainOf[r]+"/"+storage.NameOf(r))
+	}
+	return result
+}
+
+func TestFromDomains_MergesLists(t *testing.T) {
+	var domains = []*domain.Domain{domain.New("a"), domain.New("failed"), domain.New("b")}
+	var q, err = storage.NewQuery("", "")
+	require.NoError(t, err)
+
+	var errOut bytes.Buffer
+	list, domainOf, err := reader.FromDomains(context.Background(), &errOut, domains, services(), nil, q, &listing.Options{Limit: 4})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/ns-1", "b/ns-1", "a/ns-2", "b/ns-2"}, names(list, domainOf))
+	require.Contains(t, errOut.String(), "domain failed: unavailable")
+
+	list, domainOf, err = reader.FromDomains(context.Background(), &errOut, domains, services(), nil, q, &listing.Options{SortBy: ".payload", Offset: 2, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/ns-3", "b/ns-1"}, names(list, domainOf))
+
+	list, domainOf, err = reader.FromDomains(context.Background(), &errOut, domains, services(), []string{"ns-2"}, q, &listing.Options{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/ns-2", "b/ns-2"}, names(list, domainOf))
+}
+
+func TestFromDomains_Fails(t *testing.T) {
+	var q, err = storage.NewQuery("", "")
+	require.NoError(t, err)
+
+	_, _, err = reader.FromDomains(context.Background(), new(bytes.Buffer), nil, services(), nil, q, &listing.Options{})
+	require.EqualError(t, err, "no domains to query")
+
+	_, _, err = reader.FromDomains(context.Background(), new(bytes.Buffer), []*domain.Domain{domain.New("failed")}, services(), nil, q, &listing.Options{})
+	require.EqualError(t, err, "all the domains failed")
+}
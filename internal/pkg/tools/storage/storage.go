@@ -19,8 +19,14 @@ package storage
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/labels"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/patch"
 )
 
 // Resource represents NSM resource
@@ -28,21 +34,265 @@ type Resource interface {
 	fmt.Stringer
 }
 
-// Storage is abstraction on data layer
+// EventType represents a kind of the change that happened with a resource
+type EventType string
+
+const (
+	// Added means that the resource has appeared in the storage
+	Added EventType = "ADDED"
+	// Modified means that the existing resource has been changed
+	Modified EventType = "MODIFIED"
+	// Deleted means that the resource has been removed from the storage
+	Deleted EventType = "DELETED"
+)
+
+// Event represents a change of the resource in the storage
+type Event struct {
+	Type     EventType
+	Resource Resource
+}
+
+// Column describes a column of the table that represents resources of the storage
+type Column struct {
+	Name string
+	// Wide columns are printed only on request, e.g. 'nsmctl get -o wide'
+	Wide  bool
+	Value func(Resource) string
+}
+
+// Verb is an operation on resources of the storage
+type Verb string
+
+const (
+	// VerbGet reads a resource by name
+	VerbGet Verb = "get"
+	// VerbList reads all resources
+	VerbList Verb = "list"
+	// VerbWatch follows changes of the resources
+	VerbWatch Verb = "watch"
+	// VerbCreate creates a resource
+	VerbCreate Verb = "create"
+	// VerbUpdate replaces a resource
+	VerbUpdate Verb = "update"
+	// VerbDelete deletes a resource by name
+	VerbDelete Verb = "delete"
+	// VerbPatch changes a part of the resource
+	VerbPatch Verb = "patch"
+)
+
+// Verbs lists all known verbs
+var Verbs = []Verb{VerbGet, VerbList, VerbWatch, VerbCreate, VerbUpdate, VerbDelete, VerbPatch}
+
+// UnsupportedError means that the storage doesn't support the verb
+type UnsupportedError struct {
+	Verb Verb
+	Kind string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%v is not supported for resource %v", e.Verb, e.Kind)
+}
+
+// IsUnsupported returns true if the error means that the verb is not supported
+func IsUnsupported(err error) bool {
+	var target *UnsupportedError
+	return errors.As(err, &target)
+}
+
+// NotFoundError means that the storage has no resource with the name
+type NotFoundError struct {
+	Kind string
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%v %v is not found", e.Kind, e.Name)
+}
+
+// IsNotFound returns true if the error means that the resource doesn't exist
+func IsNotFound(err error) bool {
+	var target *NotFoundError
+	return errors.As(err, &target)
+}
+
+// Storage is abstraction on data layer. Operations that are not supported by the storage are nil.
 type Storage struct {
+	// Kind is the canonical name of resources of the storage
+	Kind string
+	// ShortNames are the other names of resources of the storage, e.g. plural and abbreviated forms
+	ShortNames []string
+	// Type is the type of resources of the storage
+	Type   reflect.Type
 	Get    func(context.Context, string) (Resource, error)
 	Delete func(context.Context, string) error
 	Update func(context.Context, string, Resource) error
 	List   func(context.Context) ([]Resource, error)
+	// Create returns an empty resource that can be filled and stored by Update
 	Create func(context.Context) Resource
+	// Watch calls the handler for each change of the resources until the context is done or the handler fails
+	Watch func(context.Context, func(*Event) error) error
+	// Patch applies the patch of the type to the resource, stores and returns the result
+	Patch func(context.Context, string, patch.Type, []byte) (Resource, error)
+	// Columns declares the table representation of the resources
+	Columns []Column
+	// Labels returns labels of the resource per network service
+	Labels func(Resource) map[string]map[string]string
+	// Find selects resources by the query on the server side. The result may contain extra
+	// resources that are filtered out on the client side.
+	Find func(context.Context, *Query) ([]Resource, error)
+	// ServerFields lists JSON names of the fields that are managed by the server
+	ServerFields []string
+	// PerDomain means that the resources belong to a NSM domain, the domain is selected by the context of the calls
+	PerDomain bool
+}
+
+// Registry maps names of resources to their storages
+type Registry map[string]*Storage
+
+// Register registers the storage by its kind and the short names
+func (r Registry) Register(s *Storage, shortNames ...string) {
+	s.ShortNames = append(s.ShortNames, shortNames...)
+	r[s.Kind] = s
+	for _, name := range shortNames {
+		r[name] = s
+	}
+}
+
+// Storages returns registered storages sorted by kind
+func (r Registry) Storages() []*Storage {
+	var result []*Storage
+	for name, s := range r {
+		if name == s.Kind {
+			result = append(result, s)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Kind < result[j].Kind
+	})
+	return result
+}
+
+// Supports returns true if the storage supports the verb
+func (si *Storage) Supports(v Verb) bool {
+	switch v {
+	case VerbGet:
+		return si.Get != nil
+	case VerbList:
+		return si.List != nil
+	case VerbWatch:
+		return si.Watch != nil
+	case VerbCreate:
+		return si.Create != nil && si.Update != nil
+	case VerbUpdate:
+		return si.Update != nil
+	case VerbDelete:
+		return si.Delete != nil
+	case VerbPatch:
+		return si.Patch != nil
+	}
+	return false
+}
+
+// Verbs returns the verbs supported by the storage
+func (si *Storage) Verbs() []Verb {
+	var result []Verb
+	for _, v := range Verbs {
+		if si.Supports(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Check returns *UnsupportedError if the storage doesn't support any of the verbs
+func (si *Storage) Check(verbs ...Verb) error {
+	for _, v := range verbs {
+		if !si.Supports(v) {
+			return &UnsupportedError{Verb: v, Kind: si.Kind}
+		}
+	}
+	return nil
+}
+
+// Query describes a selection of resources by labels
+type Query struct {
+	Labels labels.Selector
+	// NetworkService limits the selection to labels of the network service, all services are considered if empty
+	NetworkService string
+}
+
+// NewQuery creates a query from the label selector and the network service
+func NewQuery(selector, networkService string) (*Query, error) {
+	var sel, err = labels.Parse(selector)
+	if err != nil {
+		return nil, err
+	}
+	return &Query{Labels: sel, NetworkService: networkService}, nil
+}
+
+// Empty returns true if the query selects everything
+func (q *Query) Empty() bool {
+	return q == nil || (q.Labels.Empty() && q.NetworkService == "")
 }
 
 // Select selects resources by criteria
-func (si *Storage) Select(selector func(Resource) bool) ([]Resource, error) {
-	var list, err = si.List(context.Background())
+func (si *Storage) Select(ctx context.Context, selector func(Resource) bool) ([]Resource, error) {
+	if err := si.Check(VerbList); err != nil {
+		return nil, err
+	}
+	var list, err = si.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter(list, selector), nil
+}
+
+// SelectQuery selects resources matching the query
+func (si *Storage) SelectQuery(ctx context.Context, q *Query) ([]Resource, error) {
+	if err := si.Check(VerbList); err != nil {
+		return nil, err
+	}
+	if q.Empty() {
+		return si.List(ctx)
+	}
+	if si.Labels == nil {
+		return nil, errors.Errorf("resource %v has no labels", si.Kind)
+	}
+	if si.Find == nil {
+		return si.Select(ctx, func(r Resource) bool { return si.Matches(r, q) })
+	}
+
+	var list, err = si.Find(ctx, q)
 	if err != nil {
 		return nil, err
 	}
+
+	return filter(list, func(r Resource) bool { return si.Matches(r, q) }), nil
+}
+
+// Matches returns true if labels of the resource for any of the network services match the query
+func (si *Storage) Matches(r Resource, q *Query) bool {
+	if q.Empty() {
+		return true
+	}
+	if si.Labels == nil {
+		return false
+	}
+
+	for service, set := range si.Labels(r) {
+		if q.NetworkService != "" && q.NetworkService != service {
+			continue
+		}
+		if q.Labels.Matches(set) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func filter(list []Resource, selector func(Resource) bool) []Resource {
 	var result []Resource
 
 	for _, item := range list {
@@ -51,5 +301,31 @@ func (si *Storage) Select(selector func(Resource) bool) ([]Resource, error) {
 		}
 	}
 
-	return result, nil
+	return result
+}
+
+// NameOf returns the name of the resource. Resources without a name are identified by their id.
+func NameOf(r Resource) string {
+	switch v := r.(type) {
+	case interface{ GetName() string }:
+		return v.GetName()
+	case interface{ GetId() string }:
+		return v.GetId()
+	}
+
+	var v = reflect.ValueOf(r)
+
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	if f := v.FieldByName("Name"); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+
+	return ""
 }
@@ -0,0 +1,157 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trace renders the path of a connection hop by hop
+package trace
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const none = "<none>"
+
+// Print writes the connection and the segments of its path in order from the client to the endpoint.
+// The current hop of the path is marked with '*', the mechanism of the connection belongs to it.
+// Tokens are compared with now to show the time left.
+func Print(w io.Writer, conn *networkservice.Connection, now time.Time) error {
+	var segments = conn.GetPath().GetPathSegments()
+	var index = int(conn.GetPath().GetIndex())
+
+	var header = tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	printField(header, "CONNECTION", conn.GetId())
+	printField(header, "NETWORK SERVICE", orNone(conn.GetNetworkService()))
+	printField(header, "ENDPOINT", orNone(conn.GetNetworkServiceEndpointName()))
+	printField(header, "STATE", conn.GetState().String())
+	printField(header, "MECHANISM", fmt.Sprintf("%v (hop %v)", mechanism(conn.GetMechanism()), index))
+	var ip = conn.GetContext().GetIpContext()
+	printField(header, "NSC END", ipContext(ip.GetSrcIpAddrs(), ip.GetSrcRoutes()))
+	printField(header, "NSE END", ipContext(ip.GetDstIpAddrs(), ip.GetDstRoutes()))
+	if err := header.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(w, "\nPATH:"); err != nil {
+		return err
+	}
+	var hops = tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(hops, "\tHOP\tEND\tNAME\tID\tTOKEN-EXPIRES\tMETRICS")
+	for i, segment := range segments {
+		var current string
+		if i == index {
+			current = "*"
+		}
+		_, _ = fmt.Fprintln(hops, strings.Join([]string{
+			current,
+			fmt.Sprint(i),
+			end(i, len(segments), conn),
+			segment.GetName(),
+			segment.GetId(),
+			expires(segment, now),
+			metrics(segment.GetMetrics()),
+		}, "\t"))
+	}
+	if len(segments) == 0 {
+		_, _ = fmt.Fprintln(hops, "\t"+none)
+	}
+	return hops.Flush()
+}
+
+func printField(w io.Writer, name, value string) {
+	_, _ = fmt.Fprintf(w, "%v:\t%v\n", name, value)
+}
+
+// end tells whether the hop is the client or the endpoint of the connection
+func end(i, count int, conn *networkservice.Connection) string {
+	switch {
+	case i == 0:
+		return "NSC"
+	case i == count-1 && conn.GetPath().GetPathSegments()[i].GetName() == conn.GetNetworkServiceEndpointName():
+		return "NSE"
+	default:
+		return ""
+	}
+}
+
+func mechanism(m *networkservice.Mechanism) string {
+	if m == nil {
+		return none
+	}
+	var result = m.GetCls() + "/" + m.GetType()
+	if params := formatMap(m.GetParameters()); params != "" {
+		result += " " + params
+	}
+	return result
+}
+
+func ipContext(addrs []string, routes []*networkservice.Route) string {
+	if len(addrs) == 0 && len(routes) == 0 {
+		return none
+	}
+	var result []string
+	if len(addrs) > 0 {
+		result = append(result, "ips "+strings.Join(addrs, ","))
+	}
+	var prefixes []string
+	for _, route := range routes {
+		prefixes = append(prefixes, route.GetPrefix())
+	}
+	if len(prefixes) > 0 {
+		result = append(result, "routes "+strings.Join(prefixes, ","))
+	}
+	return strings.Join(result, ", ")
+}
+
+func expires(segment *networkservice.PathSegment, now time.Time) string {
+	if segment.GetExpires() == nil {
+		return none
+	}
+	var left = segment.GetExpires().AsTime().Sub(now).Round(time.Second)
+	if left <= 0 {
+		return "expired"
+	}
+	return left.String()
+}
+
+func metrics(m map[string]string) string {
+	if len(m) == 0 {
+		return none
+	}
+	return formatMap(m)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return none
+	}
+	return s
+}
+
+// formatMap formats the map as sorted key=value pairs
+func formatMap(m map[string]string) string {
+	var result []string
+	for k, v := range m {
+		result = append(result, k+"="+v)
+	}
+	sort.Strings(result)
+	return strings.Join(result, ",")
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/trace"
+)
+
+func TestPrint(t *testing.T) {
+	var now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	var conn = &networkservice.Connection{
+		Id:                         "conn-1",
+		NetworkService:             "ns",
+		NetworkServiceEndpointName: "nse-1",
+		State:                      networkservice.State_UP,
+		Mechanism: &networkservice.Mechanism{
+			Cls:        "LOCAL",
+			Type:       "KERNEL",
+			Parameters: map[string]string{"name": "nsm-1"},
+		},
+		Context: &networkservice.ConnectionContext{
+			IpContext: &networkservice.IPContext{
+				SrcIpAddrs: []string{"172.16.0.1/32"},
+				DstIpAddrs: []string{"172.16.0.0/32"},
+				DstRoutes:  []*networkservice.Route{{Prefix: "172.16.0.1/32"}},
+			},
+		},
+		Path: &networkservice.Path{
+			Index: 1,
+			PathSegments: []*networkservice.PathSegment{
+				{Name: "nsc-1", Id: "conn-1", Expires: timestamppb.New(now.Add(time.Minute))},
+				{Name: "nsmgr-1", Id: "id-2", Expires: timestamppb.New(now.Add(time.Minute)), Metrics: map[string]string{"tx": "1", "rx": "2"}},
+				{Name: "forwarder-1", Id: "id-3", Expires: timestamppb.New(now.Add(-time.Minute))},
+				{Name: "nse-1", Id: "id-4"},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, trace.Print(&b, conn, now))
+	require.Equal(t, `CONNECTION:        conn-1
+NETWORK SERVICE:   ns
+ENDPOINT:          nse-1
+STATE:             UP
+MECHANISM:         LOCAL/KERNEL name=nsm-1 (hop 1)
+NSC END:           ips 172.16.0.1/32
+NSE END:           ips 172.16.0.0/32, routes 172.16.0.1/32
+
+PATH:
+    HOP   END   NAME          ID       TOKEN-EXPIRES   METRICS
+    0     NSC   nsc-1         conn-1   1m0s            <none>
+*   1           nsmgr-1       id-2     1m0s            rx=2,tx=1
+    2           forwarder-1   id-3     expired         <none>
+    3     NSE   nse-1         id-4     <none>          <none>
+`, b.String())
+}
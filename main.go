@@ -18,13 +18,27 @@
 package main
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/nsmctl/cmd/diff"
 	"github.com/networkservicemesh/nsmctl/cmd/nsmctl"
 )
 
 func main() {
-	if err := nsmctl.New().Execute(); err != nil {
+	// Ctrl-C cancels requests to the domains and stops watches
+	var ctx, cancel = signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	var err = nsmctl.New().ExecuteContext(ctx)
+	cancel()
+
+	if err != nil {
+		if errors.Is(err, diff.ErrDrift) {
+			os.Exit(1)
+		}
 		os.Exit(-1)
 	}
 }
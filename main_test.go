@@ -34,8 +34,8 @@ import (
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
 	"github.com/networkservicemesh/api/pkg/api/registry"
-	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/domain"
 	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/persistence"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
 	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
 )
 
@@ -63,6 +63,11 @@ func (s *MainSuite) TestHelp() {
 	s.RequireExec("nsmctl --help")
 }
 
+func (s *MainSuite) TestAPIResources() {
+	s.RequireExec("nsmctl api-resources")
+	s.RequireExec("nsmctl explain nse.networkServiceLabels --recursive")
+}
+
 func (s *MainSuite) Test_Generate_NetworkServiceEndpoint() {
 	var dir = filepath.Join(os.Getenv("GOPATH"), "src", "my_nse_folder")
 
@@ -140,15 +145,38 @@ func (s *MainSuite) Test_SandboxAndNSMControl() {
 		},
 	}
 
-	_, err = nsc.Request(ctx, request)
+	conn, err := nsc.Request(ctx, request)
 	require.NoError(s.T(), err)
 
 	s.RequireExec("nsmctl get domains --domain test")
 	s.RequireExec("nsmctl get nses --domain test")
 	s.RequireExec("nsmctl get netsvc --domain test")
 	s.RequireExec("nsmctl get connections --domain test")
+	s.RequireExec("nsmctl get nses --domains test")
+	s.RequireExec("nsmctl get nses --domain test --request-timeout 10s --dial-timeout 10s --retries 1 --retry-backoff 100ms")
+	s.RequireExec("nsmctl get nse final-endpoint@test")
+	s.RequireExec("nsmctl get nses --domain test --sort-by .expirationTime --field-selector networkServiceNames=ns --limit 1")
+
+	s.RequireExec("nsmctl trace connection --domain test " + conn.GetPath().GetPathSegments()[1].GetId())
+	s.RequireExec("nsmctl trace connection --domain test " + conn.GetId())
 
 	s.RequireExec("nsmctl describe domains")
+	s.RequireExec("nsmctl check domain test")
+
+	defer func() {
+		_ = persistence.Delete[*domain.Domain]("test-imperative")
+	}()
+	s.RequireExec("nsmctl create domain test-imperative --registry registry.nsm-system --manager 127.0.0.1:5001 --insecure")
+	s.RequireExec("nsmctl get domain test-imperative -o yaml")
+	defer func() {
+		_ = domain.DeleteContext("test-context")
+	}()
+	s.RequireExec("nsmctl config set-context test-context --domain test --output wide")
+	s.RequireExec("nsmctl config use-context test-context")
+	s.RequireExec("nsmctl config get-contexts")
+	s.RequireExec("nsmctl get nses")
+	s.RequireExec("nsmctl config delete-context test-context")
+
 	s.RequireExec("nsmctl describe nses --domain test")
 	s.RequireExec("nsmctl describe netsvc --domain test")
 	s.RequireExec("nsmctl describe connections --domain test")
@@ -157,12 +185,19 @@ func (s *MainSuite) Test_SandboxAndNSMControl() {
 	_ = os.WriteFile(p, []byte("name: my-nse"), os.ModePerm)
 	s.RequireExec("nsmctl apply nse --domain test -f " + p)
 	s.RequireExec("nsmctl get nse --domain test my-nse")
+	s.RequireExec("nsmctl patch nse --domain test my-nse -p '{\"networkServiceNames\":[\"ns\"]}'")
 	s.RequireExec("nsmctl delete nse --domain test my-nse")
 
 	p = filepath.Join(s.T().TempDir(), "ns.yaml")
 	_ = os.WriteFile(p, []byte("name: my-ns"), os.ModePerm)
 	s.RequireExec("nsmctl apply netsvc --domain test -f " + p)
 	s.RequireExec("nsmctl get netsvc --domain test my-ns")
+	var diffPath = filepath.Join(s.T().TempDir(), "ns-diff.yaml")
+	_ = os.WriteFile(diffPath, []byte("kind: NetworkService\nname: my-ns\npayload: IP"), os.ModePerm)
+	s.RequireExec("nsmctl diff --domain test --ignore-server-fields -f " + diffPath)
+	s.RequireExec("nsmctl edit netsvc --domain test my-ns",
+		exechelper.WithEnvirons(os.Environ()...),
+		exechelper.WithEnvKV("EDITOR", "sed -i -e '$a payload: ETHERNET'"))
 	s.RequireExec("nsmctl delete netsvc --domain test my-ns")
 }
 
@@ -0,0 +1,163 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+)
+
+// Token is the JWT the client sends with requests to a service of the domain
+type Token struct {
+	// Raw is the signed token
+	Raw string
+	// Algorithm is the signing algorithm of the token
+	Algorithm string
+	// Claims are the claims of the token, the audience is the SPIFFE ID of the server
+	Claims *jwt.RegisteredClaims
+	// Expires is when the client stops using the token, it may be earlier than the exp claim if the certificate
+	// of the server expires earlier
+	Expires time.Time
+	// Server is the address of the server the token has been minted for
+	Server string
+}
+
+// IdentitySource describes where the X509-SVID of the client comes from
+func (c *Client) IdentitySource() string {
+	if c.domain.CertFile != "" {
+		return "certificate " + c.domain.CertFile
+	}
+	return "workload API " + workloadAPISocket(c.domain)
+}
+
+// SVID returns the X509-SVID the client presents to the servers of the domain
+func (c *Client) SVID(ctx context.Context) (*x509svid.SVID, error) {
+	if err := c.secure(); err != nil {
+		return nil, err
+	}
+
+	var identityCtx, cancel = ctx, context.CancelFunc(func() {})
+	if c.options.dialTimeout > 0 {
+		identityCtx, cancel = context.WithTimeout(ctx, c.options.dialTimeout)
+	}
+	defer cancel()
+
+	var svids, _, err = c.dialer.identity(identityCtx, c.domain)
+	if err != nil {
+		return nil, stageError(ctx, identityCtx, err, "getting the X509-SVID of the domain "+c.domain.Name, c.options.dialTimeout)
+	}
+	return svids.GetX509SVID()
+}
+
+// Token mints the JWT the client sends with requests to the service of the domain the same way the connections do.
+// The service is connected to because the token is minted for the SPIFFE ID of the server.
+func (c *Client) Token(ctx context.Context, service string) (*Token, error) {
+	if err := c.secure(); err != nil {
+		return nil, err
+	}
+	var d = c.domain
+
+	if c.options.dialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.options.dialTimeout)
+		defer cancel()
+	}
+
+	var svids, bundles, err = c.dialer.identity(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	var config *tls.Config
+	if config, err = newTLSConfig(d, svids, bundles); err != nil {
+		return nil, err
+	}
+	var state tls.ConnectionState
+	var server string
+	if state, server, err = handshake(ctx, c.domain, service, config); err != nil {
+		return nil, err
+	}
+
+	var lifetime time.Duration
+	if lifetime, err = d.TokenLifetimeOrDefault(); err != nil {
+		return nil, err
+	}
+
+	var result = &Token{Server: server, Claims: new(jwt.RegisteredClaims)}
+	if result.Raw, result.Expires, err = spiffejwt.TokenGeneratorFunc(svids, lifetime)(credentials.TLSInfo{State: state}); err != nil {
+		return nil, err
+	}
+
+	var svid *x509svid.SVID
+	if svid, err = svids.GetX509SVID(); err != nil {
+		return nil, err
+	}
+	var parsed *jwt.Token
+	if parsed, err = jwt.ParseWithClaims(result.Raw, result.Claims, func(*jwt.Token) (interface{}, error) {
+		return svid.Certificates[0].PublicKey, nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "the minted token doesn't verify with the X509-SVID")
+	}
+	result.Algorithm = parsed.Method.Alg()
+
+	return result, nil
+}
+
+// secure returns an error if the client sends no identity to the domain
+func (c *Client) secure() error {
+	if c.domain.IsInsecure {
+		return errors.Errorf("domain %v is insecure, nsmctl presents no identity to it", c.domain.Name)
+	}
+	return nil
+}
+
+// handshake makes a TLS handshake with the first replica of the service that accepts it, returns the state of the
+// connection and the address of the replica
+func handshake(ctx context.Context, d *domain.Domain, service string, config *tls.Config) (tls.ConnectionState, string, error) {
+	var addresses = []string{service}
+	if !isAddress(service) {
+		var err error
+		if addresses, err = resolveService(ctx, d, service); err != nil {
+			return tls.ConnectionState{}, "", errors.Wrapf(err, "DNS lookup of %v of the domain %v failed", service, d.Name)
+		}
+	}
+
+	config = config.Clone()
+	config.NextProtos = []string{"h2"}
+
+	var errs []string
+	for _, addr := range addresses {
+		var conn, err = (&tls.Dialer{Config: config}).DialContext(ctx, "tcp", addr)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		var state = conn.(*tls.Conn).ConnectionState()
+		_ = conn.Close()
+		return state, addr, nil
+	}
+	return tls.ConnectionState{}, "", errors.Errorf("TLS handshake with %v of the domain %v failed: %v", service, d.Name, strings.Join(errs, "; "))
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package client_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/nsmctl/pkg/client"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+// issue creates a certificate signed by the parent, a self-signed CA if the parent is nil
+func issue(t *testing.T, id string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	var key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var template = &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{Organization: []string{"test"}},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageKeyAgreement,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	var u, parseErr = url.Parse(id)
+	require.NoError(t, parseErr)
+	template.URIs = []*url.URL{u}
+
+	if parent == nil {
+		template.IsCA, template.BasicConstraintsValid = true, true
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func writePEM(t *testing.T, path, kind string, der []byte) {
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0o600))
+}
+
+func TestClient_Token(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var ca, caKey = issue(t, "spiffe://example.org", nil, nil)
+	var clientCert, clientKey = issue(t, "spiffe://example.org/nsmctl", ca, caKey)
+	var serverCert, serverKey = issue(t, "spiffe://example.org/registry", ca, caKey)
+
+	var dir = t.TempDir()
+	writePEM(t, filepath.Join(dir, "ca.pem"), "CERTIFICATE", ca.Raw)
+	writePEM(t, filepath.Join(dir, "cert.pem"), "CERTIFICATE", clientCert.Raw)
+	var keyDER, err = x509.MarshalPKCS8PrivateKey(clientKey)
+	require.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "key.pem"), "PRIVATE KEY", keyDER)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2"},
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+	})
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+	go func() {
+		for {
+			var conn, acceptErr = listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+
+	var c = client.New(&domain.Domain{
+		Name:            "test",
+		RegistryService: listener.Addr().String(),
+		CertFile:        filepath.Join(dir, "cert.pem"),
+		KeyFile:         filepath.Join(dir, "key.pem"),
+		CAFile:          filepath.Join(dir, "ca.pem"),
+		ServerID:        "spiffe://example.org/registry",
+	})
+	defer func() { require.NoError(t, c.Close()) }()
+
+	svid, err := c.SVID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "spiffe://example.org/nsmctl", svid.ID.String())
+
+	token, err := c.Token(ctx, listener.Addr().String())
+	require.NoError(t, err)
+	require.Equal(t, "ES256", token.Algorithm)
+	require.Equal(t, "spiffe://example.org/nsmctl", token.Claims.Subject)
+	require.Equal(t, []string{"spiffe://example.org/registry"}, []string(token.Claims.Audience))
+	require.Equal(t, listener.Addr().String(), token.Server)
+
+	var insecure = client.New(&domain.Domain{Name: "test", IsInsecure: true})
+	_, err = insecure.SVID(ctx)
+	require.Error(t, err)
+}
+
+func TestClient_SVIDTimeout(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var c = client.New(&domain.Domain{
+		Name:              "test",
+		WorkloadAPISocket: "unix://" + filepath.Join(t.TempDir(), "agent.sock"),
+	}, client.WithDialTimeout(100*time.Millisecond))
+	defer func() { require.NoError(t, c.Close()) }()
+
+	var _, err = c.SVID(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "getting the X509-SVID of the domain test timed out after 100ms")
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+)
+
+// Names of the stages of connecting to a service of the domain
+const (
+	StageDNSServer = "dns-server"
+	StageSRV       = "srv-lookup"
+	StageIP        = "ip-lookup"
+	StageTCP       = "tcp-connect"
+	StageSVID      = "svid"
+	StageTLS       = "tls-handshake"
+	StageAPI       = "api-call"
+)
+
+// hints explain what to check if the stage fails
+var hints = map[string]string{
+	StageDNSServer: "check that the DNS server of the domain is running and reachable, or unset it to use the system resolver",
+	StageSRV:       "check the name of the domain and the service, the DNS server must serve SRV records of the services of the domain",
+	StageIP:        "check that the DNS server has A/AAAA records of the targets of the SRV records",
+	StageTCP:       "check that the service is exposed outside of the cluster and no firewall blocks the port",
+	StageSVID:      "check the workload API socket of the domain and that the SPIRE agent has an entry for nsmctl, or the certificate files",
+	StageTLS:       "check the CA bundle, the server SPIFFE ID or trust domain of the domain, or use --insecure for plain text services",
+	StageAPI:       "check that the address points to the right service and that the policies of the domain allow nsmctl",
+}
+
+// Stage is the result of a stage of connecting to a service of the domain
+type Stage struct {
+	// Service is the service of the domain, e.g. registry.nsm-system
+	Service string
+	// Name is the name of the stage, e.g. srv-lookup
+	Name string
+	// Skipped means that the stage doesn't apply to the domain or a previous stage has failed
+	Skipped bool
+	// Detail describes the result, e.g. the resolved address
+	Detail string
+	// Err is the error of the stage, nil if it has passed
+	Err error
+	// Duration is the time the stage took
+	Duration time.Duration
+}
+
+// Hint returns a remediation hint for the failed stage
+func (s *Stage) Hint() string {
+	if s.Err == nil {
+		return ""
+	}
+	return hints[s.Name]
+}
+
+// Check connects to the registry and the manager of the domain stage by stage, the same way the client dials them,
+// and reports each stage. Stages after a failed one are skipped. The timeout limits each stage.
+func (c *Client) Check(ctx context.Context, timeout time.Duration) []*Stage {
+	var result []*Stage
+	result = append(result, c.check(ctx, timeout, c.domain.RegistryService, func(ctx context.Context) (string, error) {
+		var list, err = c.NetworkServices().List(ctx, nil)
+		return fmt.Sprintf("found %v network services", len(list)), err
+	})...)
+	result = append(result, c.check(ctx, timeout, c.domain.ManagerService, func(ctx context.Context) (string, error) {
+		var list, err = c.Connections().List(ctx)
+		return fmt.Sprintf("found %v connections", len(list)), err
+	})...)
+	return result
+}
+
+type checker struct {
+	ctx     context.Context
+	timeout time.Duration
+	service string
+	stages  []*Stage
+	failed  bool
+}
+
+// run runs the stage unless a previous one has failed
+func (c *checker) run(name string, f func(ctx context.Context) (string, error)) {
+	var s = &Stage{Service: c.service, Name: name}
+	c.stages = append(c.stages, s)
+
+	if c.failed {
+		s.Skipped = true
+		s.Detail = "previous stage failed"
+		return
+	}
+
+	var ctx, cancel = context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	var start = time.Now()
+	s.Detail, s.Err = f(ctx)
+	s.Duration = time.Since(start)
+	c.failed = s.Err != nil
+}
+
+func (c *checker) skip(name, reason string) {
+	c.stages = append(c.stages, &Stage{Service: c.service, Name: name, Skipped: true, Detail: reason})
+}
+
+func (c *Client) check(ctx context.Context, timeout time.Duration, service string, call func(context.Context) (string, error)) []*Stage {
+	var d = c.domain
+	var ch = &checker{ctx: ctx, timeout: timeout, service: service}
+	var addresses = []string{service}
+	var address string
+
+	if isAddress(service) {
+		ch.skip(StageDNSServer, "the service is an address")
+		ch.skip(StageSRV, "the service is an address")
+		ch.skip(StageIP, "the service is an address")
+	} else {
+		var r = newResolver(d)
+
+		if d.DNSServerAddress == "" {
+			ch.skip(StageDNSServer, "the system resolver is used")
+		} else {
+			// Any answer about the domain means that the server is running, NXDOMAIN as well
+			ch.run(StageDNSServer, func(ctx context.Context) (string, error) {
+				var _, err = r.LookupNS(ctx, d.Name+".")
+				var dnsErr *net.DNSError
+				if err != nil && (!errors.As(err, &dnsErr) || !dnsErr.IsNotFound) {
+					return "", err
+				}
+				return d.DNSServerAddress + " answers", nil
+			})
+		}
+
+		var records []*net.SRV
+		ch.run(StageSRV, func(ctx context.Context) (string, error) {
+			var err error
+			if records, err = lookupSRV(ctx, r, d.FQDN(service)); err != nil {
+				return "", err
+			}
+			var targets []string
+			for _, record := range records {
+				targets = append(targets, fmt.Sprintf("%v port %v priority %v weight %v", record.Target, record.Port, record.Priority, record.Weight))
+			}
+			return strings.Join(targets, ", "), nil
+		})
+		ch.run(StageIP, func(ctx context.Context) (string, error) {
+			var err error
+			if addresses, err = lookupAddresses(ctx, r, records); err != nil {
+				return "", err
+			}
+			return strings.Join(addresses, ", "), nil
+		})
+	}
+
+	// Replicas are tried in the order of preference the same way gRPC fails over between them
+	ch.run(StageTCP, func(ctx context.Context) (string, error) {
+		var errs []string
+		for _, addr := range addresses {
+			var conn, err = new(net.Dialer).DialContext(ctx, "tcp", addr)
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			_ = conn.Close()
+			address = addr
+			if len(errs) > 0 {
+				return fmt.Sprintf("%v, %v preferred replicas are unreachable", addr, len(errs)), nil
+			}
+			return addr, nil
+		}
+		return "", errors.New(strings.Join(errs, "; "))
+	})
+
+	c.checkIdentity(ch, address)
+
+	ch.run(StageAPI, call)
+
+	return ch.stages
+}
+
+// checkIdentity runs the stages of getting the X509-SVID and the TLS handshake with the address
+func (c *Client) checkIdentity(ch *checker, address string) {
+	var d = c.domain
+	if d.IsInsecure {
+		ch.skip(StageSVID, "the domain is insecure")
+		ch.skip(StageTLS, "the domain is insecure")
+		return
+	}
+
+	var tlsConfig *tls.Config
+	ch.run(StageSVID, func(ctx context.Context) (string, error) {
+		var svids, bundles, err = c.dialer.identity(ctx, d)
+		if err != nil {
+			return "", err
+		}
+		var svid, svidErr = svids.GetX509SVID()
+		if svidErr != nil {
+			return "", svidErr
+		}
+		if tlsConfig, err = newTLSConfig(d, svids, bundles); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%v, expires %v", svid.ID, svid.Certificates[0].NotAfter.Format(time.RFC3339)), nil
+	})
+	ch.run(StageTLS, func(ctx context.Context) (string, error) {
+		return checkHandshake(ctx, tlsConfig, address)
+	})
+}
+
+// checkHandshake makes a TLS handshake with the address, the SPIFFE ID of the server is reported even if it is rejected
+func checkHandshake(ctx context.Context, tlsConfig *tls.Config, address string) (string, error) {
+	var peer spiffeid.ID
+	var config = tlsConfig.Clone()
+	config.NextProtos = []string{"h2"}
+	var verify = config.VerifyPeerCertificate
+	config.VerifyPeerCertificate = func(raw [][]byte, chains [][]*x509.Certificate) error {
+		if len(raw) > 0 {
+			if cert, err := x509.ParseCertificate(raw[0]); err == nil && len(cert.URIs) > 0 {
+				peer, _ = spiffeid.FromURI(cert.URIs[0])
+			}
+		}
+		return verify(raw, chains)
+	}
+	var conn, err = (&tls.Dialer{Config: config}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		if !peer.IsZero() {
+			return "", errors.Wrapf(err, "server %v", peer)
+		}
+		return "", err
+	}
+	_ = conn.Close()
+	return "server " + peer.String(), nil
+}
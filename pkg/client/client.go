@@ -0,0 +1,127 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client provides API to work with resources of a NSM domain: network services, network service endpoints
+// and connections. The client dials the registry and the manager of the domain the same way as nsmctl does.
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+// Client works with resources of a NSM domain. Connections are created on the first use and are kept until Close.
+type Client struct {
+	domain  *domain.Domain
+	options *options
+	dialer  *dialer
+}
+
+// New creates a client for the domain
+func New(d *domain.Domain, opts ...Option) *Client {
+	var o = newOptions(opts)
+	return &Client{
+		domain:  d,
+		options: o,
+		dialer:  newDialer(o.dialTimeout),
+	}
+}
+
+// ForDomain creates a client for the stored domain with the name. The default domain is used if the name is empty.
+func ForDomain(name string, opts ...Option) (*Client, error) {
+	var d *domain.Domain
+	var err error
+
+	if name == "" {
+		d, err = domain.Current()
+	} else {
+		d, err = domain.Load(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return New(d, opts...), nil
+}
+
+// Domain returns the domain of the client
+func (c *Client) Domain() *domain.Domain {
+	return c.domain
+}
+
+// Close closes connections of the client
+func (c *Client) Close() error {
+	return c.dialer.Close()
+}
+
+// NetworkServices returns the client of the network services of the domain
+func (c *Client) NetworkServices() *NetworkServices {
+	return &NetworkServices{client: c}
+}
+
+// NetworkServiceEndpoints returns the client of the network service endpoints of the domain
+func (c *Client) NetworkServiceEndpoints() *NetworkServiceEndpoints {
+	return &NetworkServiceEndpoints{client: c}
+}
+
+// Connections returns the client of the connections of the domain
+func (c *Client) Connections() *Connections {
+	return &Connections{client: c}
+}
+
+// NotFoundError means that the domain has no resource with the name
+type NotFoundError struct {
+	Kind string
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%v %v is not found", e.Kind, e.Name)
+}
+
+// IsNotFound returns true if the error means that the resource doesn't exist
+func IsNotFound(err error) bool {
+	var target *NotFoundError
+	return errors.As(err, &target)
+}
+
+// readAll reads the stream until its end, the responses read before an error are returned with the error
+func readAll[T any](recv func() (T, error)) ([]T, error) {
+	var result []T
+	for {
+		var resp, err = recv()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+		result = append(result, resp)
+	}
+}
+
+// closed returns the result of the watch that has been finished by the error of the stream
+func closed(ctx context.Context, err error) error {
+	if errors.Is(err, io.EOF) || ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package client_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/nsmctl/pkg/client"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+func TestClient_NetworkServiceEndpoints(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var d = sandbox.NewBuilder(ctx, t).SetNodesCount(0).Build()
+
+	var c = client.New(&domain.Domain{
+		Name:            "test",
+		RegistryService: net.JoinHostPort(d.Registry.URL.Hostname(), d.Registry.URL.Port()),
+		IsInsecure:      true,
+	})
+	defer func() { require.NoError(t, c.Close()) }()
+
+	var nses = c.NetworkServiceEndpoints()
+
+	_, err := nses.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1", NetworkServiceNames: []string{"ns"}})
+	require.NoError(t, err)
+	_, err = nses.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-10", NetworkServiceNames: []string{"ns"}})
+	require.NoError(t, err)
+
+	nse, err := nses.Get(ctx, "nse-1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ns"}, nse.GetNetworkServiceNames())
+
+	list, err := nses.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	require.NoError(t, nses.Unregister(ctx, "nse-1"))
+
+	_, err = nses.Get(ctx, "nse-1")
+	require.True(t, client.IsNotFound(err))
+}
+
+type unavailableRegistry struct {
+	registry.UnimplementedNetworkServiceRegistryServer
+	failures int32
+	calls    int32
+}
+
+func (r *unavailableRegistry) Find(_ *registry.NetworkServiceQuery, server registry.NetworkServiceRegistry_FindServer) error {
+	if atomic.AddInt32(&r.calls, 1) <= r.failures {
+		return status.Error(codes.Unavailable, "registry is restarting")
+	}
+	return server.Send(&registry.NetworkServiceResponse{NetworkService: &registry.NetworkService{Name: "ns"}})
+}
+
+func TestClient_Retry(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var listener, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var server = grpc.NewServer()
+	var r = &unavailableRegistry{failures: 2}
+	registry.RegisterNetworkServiceRegistryServer(server, r)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	var d = &domain.Domain{Name: "test", RegistryService: listener.Addr().String(), IsInsecure: true}
+
+	var c = client.New(d, client.WithRetry(2, time.Millisecond))
+	defer func() { require.NoError(t, c.Close()) }()
+
+	list, err := c.NetworkServices().List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, int32(3), atomic.LoadInt32(&r.calls))
+
+	var noRetries = client.New(d)
+	defer func() { require.NoError(t, noRetries.Close()) }()
+
+	atomic.StoreInt32(&r.calls, 0)
+	_, err = noRetries.NetworkServices().List(ctx, nil)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestClient_Timeouts(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var listener, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	var closedAddress = listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	var c = client.New(&domain.Domain{Name: "test", RegistryService: closedAddress, IsInsecure: true}, client.WithDialTimeout(100*time.Millisecond))
+	defer func() { require.NoError(t, c.Close()) }()
+
+	_, err = c.NetworkServices().List(ctx, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "connection of "+closedAddress+" of the domain test timed out after 100ms")
+
+	var d = sandbox.NewBuilder(ctx, t).SetNodesCount(0).Build()
+	var canceledCtx, cancelRequest = context.WithCancel(ctx)
+	cancelRequest()
+
+	var available = client.New(&domain.Domain{
+		Name:            "test",
+		RegistryService: net.JoinHostPort(d.Registry.URL.Hostname(), d.Registry.URL.Port()),
+		IsInsecure:      true,
+	}, client.WithRequestTimeout(time.Second))
+	defer func() { require.NoError(t, available.Close()) }()
+
+	_, err = available.NetworkServices().List(ctx, nil)
+	require.NoError(t, err)
+	_, err = available.NetworkServices().List(canceledCtx, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has been canceled")
+}
+
+func TestClient_Redial(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var listener, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	var address = listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	var d = &domain.Domain{Name: "test", RegistryService: address, IsInsecure: true}
+	var c = client.New(d, client.WithDialTimeout(100*time.Millisecond))
+	defer func() { require.NoError(t, c.Close()) }()
+
+	_, err = c.NetworkServices().List(ctx, nil)
+	require.Error(t, err)
+
+	var serve = func() {
+		var l, listenErr = net.Listen("tcp", address)
+		if listenErr != nil {
+			t.Error(listenErr)
+			return
+		}
+		var server = grpc.NewServer()
+		registry.RegisterNetworkServiceRegistryServer(server, new(unavailableRegistry))
+		go func() { _ = server.Serve(l) }()
+		t.Cleanup(server.Stop)
+	}
+
+	// The failed dial is not kept, the next request connects once the registry is up
+	serve()
+	list, err := c.NetworkServices().List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address = listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	// Each retry dials again
+	var retrying = client.New(&domain.Domain{Name: "test", RegistryService: address, IsInsecure: true},
+		client.WithDialTimeout(100*time.Millisecond), client.WithRetry(10, 50*time.Millisecond))
+	defer func() { require.NoError(t, retrying.Close()) }()
+
+	time.AfterFunc(300*time.Millisecond, serve)
+	list, err = retrying.NetworkServices().List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+}
+
+func TestClient_Check(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var d = sandbox.NewBuilder(ctx, t).SetNodesCount(0).Build()
+
+	var listener, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	var closedAddress = listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	var c = client.New(&domain.Domain{
+		Name:            "test",
+		RegistryService: net.JoinHostPort(d.Registry.URL.Hostname(), d.Registry.URL.Port()),
+		ManagerService:  closedAddress,
+		IsInsecure:      true,
+	})
+	defer func() { require.NoError(t, c.Close()) }()
+
+	var results = make(map[string]string)
+	for _, s := range c.Check(ctx, time.Second) {
+		var result = "pass"
+		switch {
+		case s.Skipped:
+			result = "skip"
+		case s.Err != nil:
+			result = "fail"
+			require.NotEmpty(t, s.Hint())
+		}
+		results[s.Service+" "+s.Name] = result
+	}
+
+	var registryAddress = d.Registry.URL.Host
+	require.Equal(t, "skip", results[registryAddress+" "+client.StageSRV])
+	require.Equal(t, "pass", results[registryAddress+" "+client.StageTCP])
+	require.Equal(t, "skip", results[registryAddress+" "+client.StageTLS])
+	require.Equal(t, "pass", results[registryAddress+" "+client.StageAPI])
+	require.Equal(t, "fail", results[closedAddress+" "+client.StageTCP])
+	require.Equal(t, "skip", results[closedAddress+" "+client.StageAPI])
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// Connections works with connections of the domain manager
+type Connections struct {
+	client *Client
+}
+
+// Get returns the connection with the id, *NotFoundError if there is no such connection
+func (s *Connections) Get(ctx context.Context, id string) (*networkservice.Connection, error) {
+	var connections, err = s.snapshot(ctx, &networkservice.PathSegment{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := connections[id]; ok {
+		return v, nil
+	}
+	return nil, &NotFoundError{Kind: "connection", Name: id}
+}
+
+// List returns all connections
+func (s *Connections) List(ctx context.Context) ([]*networkservice.Connection, error) {
+	var connections, err = s.snapshot(ctx, &networkservice.PathSegment{})
+	if err != nil {
+		return nil, err
+	}
+	var result []*networkservice.Connection
+	for _, item := range connections {
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// Watch calls the handler for the current state of the connections and for all their changes
+// until the context is done or the handler fails
+func (s *Connections) Watch(ctx context.Context, handler func(*networkservice.ConnectionEvent) error) error {
+	var d = s.client.domain
+	var cc, err = s.client.dialer.dial(ctx, d, d.ManagerService)
+	if err != nil {
+		return err
+	}
+	stream, err := networkservice.NewMonitorConnectionClient(cc).MonitorConnections(ctx, &networkservice.MonitorScopeSelector{PathSegments: []*networkservice.PathSegment{{}}})
+	if err != nil {
+		return err
+	}
+	for {
+		event, recvErr := stream.Recv()
+		if recvErr != nil {
+			return closed(ctx, recvErr)
+		}
+		if err = handler(event); err != nil {
+			return err
+		}
+	}
+}
+
+// snapshot returns the initial state of the connections that match the path segment
+func (s *Connections) snapshot(ctx context.Context, segment *networkservice.PathSegment) (map[string]*networkservice.Connection, error) {
+	var result map[string]*networkservice.Connection
+	var err = s.client.retry(ctx, s.client.domain.ManagerService, func(ctx context.Context, cc grpc.ClientConnInterface) error {
+		monitorCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var stream, monitorErr = networkservice.NewMonitorConnectionClient(cc).MonitorConnections(monitorCtx, &networkservice.MonitorScopeSelector{PathSegments: []*networkservice.PathSegment{segment}})
+		if monitorErr != nil {
+			return monitorErr
+		}
+		var event, recvErr = stream.Recv()
+		if recvErr != nil {
+			return recvErr
+		}
+		result = event.GetConnections()
+		return nil
+	})
+	return result, err
+}
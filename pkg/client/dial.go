@@ -0,0 +1,320 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edwarnicke/grpcfd"
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+)
+
+const defaultWorkloadAPISocket = "unix:///tmp/spire-agent/public/api.sock"
+
+// dialer keeps gRPC connections of the client. Each target of a domain is dialed within the timeout until it connects,
+// failed dials are not kept so the next call dials again. The connections share X509 sources per workload API socket.
+// Close releases everything, the dialer can be used again after it.
+type dialer struct {
+	timeout time.Duration
+	mu      sync.Mutex
+	conns   map[string]*clientConn
+	sources map[string]*x509Source
+}
+
+type clientConn struct {
+	mu sync.Mutex
+	cc *grpc.ClientConn
+}
+
+type x509Source struct {
+	mu     sync.Mutex
+	source *workloadapi.X509Source
+}
+
+func newDialer(timeout time.Duration) *dialer {
+	return &dialer{
+		timeout: timeout,
+		conns:   make(map[string]*clientConn),
+		sources: make(map[string]*x509Source),
+	}
+}
+
+// dial returns a connection to the target of the domain, the connection is created on the first successful call.
+// Concurrent calls wait for the same dial.
+func (m *dialer) dial(ctx context.Context, d *domain.Domain, target string) (grpc.ClientConnInterface, error) {
+	m.mu.Lock()
+	var key = d.Name + "/" + target
+	var c, ok = m.conns[key]
+	if !ok {
+		c = new(clientConn)
+		m.conns[key] = c
+	}
+	m.mu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cc == nil {
+		var cc, err = m.newConn(ctx, d, target)
+		if err != nil {
+			return nil, err
+		}
+		c.cc = cc
+	}
+
+	return c.cc, nil
+}
+
+// Close closes all the connections and the X509 source
+func (m *dialer) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result error
+
+	for _, c := range m.conns {
+		c.mu.Lock()
+		if c.cc != nil {
+			if err := c.cc.Close(); err != nil && result == nil {
+				result = err
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	for _, s := range m.sources {
+		s.mu.Lock()
+		if s.source != nil {
+			if err := s.source.Close(); err != nil && result == nil {
+				result = err
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	m.conns = make(map[string]*clientConn)
+	m.sources = make(map[string]*x509Source)
+
+	return result
+}
+
+// x509Source returns the source of the workload API at the address, the source is created on the first successful call
+func (m *dialer) x509Source(ctx context.Context, addr string) (*workloadapi.X509Source, error) {
+	m.mu.Lock()
+	var s, ok = m.sources[addr]
+	if !ok {
+		s = new(x509Source)
+		m.sources[addr] = s
+	}
+	m.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.source == nil {
+		var source, err = workloadapi.NewX509Source(ctx, workloadapi.WithClientOptions(workloadapi.WithAddr(addr)))
+		if err != nil {
+			return nil, err
+		}
+		s.source = source
+	}
+
+	return s.source, nil
+}
+
+func (m *dialer) newConn(ctx context.Context, d *domain.Domain, service string) (*grpc.ClientConn, error) {
+	var dialCtx, cancel = ctx, context.CancelFunc(func() {})
+	if m.timeout > 0 {
+		dialCtx, cancel = context.WithTimeout(ctx, m.timeout)
+	}
+	defer cancel()
+
+	var conn, stage, err = m.connect(dialCtx, d, service)
+	if err != nil {
+		return nil, stageError(ctx, dialCtx, err, fmt.Sprintf("%v of %v of the domain %v", stage, service, d.Name), m.timeout)
+	}
+	return conn, nil
+}
+
+// stageError wraps the error of the stage with the reason the stage has stopped. ctx is the context of the caller,
+// stageCtx is ctx limited by the timeout.
+func stageError(ctx, stageCtx context.Context, err error, stage string, timeout time.Duration) error {
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		return errors.Wrapf(err, "%v has been canceled", stage)
+	case ctx.Err() != nil:
+		return errors.Wrapf(err, "%v timed out", stage)
+	case stageCtx.Err() != nil:
+		return errors.Wrapf(err, "%v timed out after %v", stage, timeout)
+	default:
+		return errors.Wrapf(err, "%v failed", stage)
+	}
+}
+
+// connect connects to the service of the domain, returns the stage that has failed with the error.
+// Addresses are dialed as is, service names are resolved by SRV records into all the replicas of the service.
+func (m *dialer) connect(ctx context.Context, d *domain.Domain, service string) (*grpc.ClientConn, string, error) {
+	var target, stage = service, "connection"
+	var dialOptions []grpc.DialOption
+
+	if !isAddress(service) {
+		var addresses, err = resolveService(ctx, d, service)
+		if err != nil {
+			return nil, "DNS lookup", err
+		}
+		var builder = newSRVResolverBuilder(d, service, addresses)
+		target, stage = builder.target(), "connection to "+strings.Join(addresses, ", ")
+		dialOptions = append(dialOptions, grpc.WithResolvers(builder))
+	}
+
+	if d.IsInsecure {
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		svids, bundles, err := m.identity(ctx, d)
+		if err != nil {
+			return nil, "getting the X509-SVID", err
+		}
+
+		tlsClientConfig, err := newTLSConfig(d, svids, bundles)
+		if err != nil {
+			return nil, "TLS configuration", err
+		}
+
+		lifetime, err := d.TokenLifetimeOrDefault()
+		if err != nil {
+			return nil, "token configuration", err
+		}
+
+		dialOptions = append(dialOptions,
+			grpc.WithTransportCredentials(
+				grpcfd.TransportCredentials(credentials.NewTLS(tlsClientConfig))),
+			grpc.WithDefaultCallOptions(
+				grpc.PerRPCCredentials(token.NewPerRPCCredentials(spiffejwt.TokenGeneratorFunc(svids, lifetime))),
+			),
+			grpcfd.WithChainStreamInterceptor(),
+			grpcfd.WithChainUnaryInterceptor(),
+		)
+	}
+
+	dialOptions = append([]grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.WithReturnConnectionError(),
+	}, dialOptions...)
+
+	cc, err := grpc.DialContext(ctx, target, dialOptions...)
+	if err != nil {
+		return nil, stage, err
+	}
+	return cc, "", nil
+}
+
+// identity returns the X509-SVID of nsmctl and the bundles to verify servers of the domain. Static files of the domain
+// are used if set, otherwise the identity comes from the workload API.
+func (m *dialer) identity(ctx context.Context, d *domain.Domain) (x509svid.Source, x509bundle.Source, error) {
+	if d.CertFile == "" {
+		var source, err = m.x509Source(ctx, workloadAPISocket(d))
+		if err != nil {
+			return nil, nil, err
+		}
+		return source, source, nil
+	}
+
+	var svid, err = x509svid.Load(d.CertFile, d.KeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	var trustDomain = svid.ID.TrustDomain()
+	if d.TrustDomain != "" {
+		if trustDomain, err = spiffeid.TrustDomainFromString(d.TrustDomain); err != nil {
+			return nil, nil, err
+		}
+	}
+	if d.ServerID != "" {
+		var id spiffeid.ID
+		if id, err = spiffeid.FromString(d.ServerID); err != nil {
+			return nil, nil, err
+		}
+		trustDomain = id.TrustDomain()
+	}
+	var bundle *x509bundle.Bundle
+	if bundle, err = x509bundle.Load(trustDomain, d.CAFile); err != nil {
+		return nil, nil, err
+	}
+	return svid, bundle, nil
+}
+
+// newTLSConfig creates the mTLS configuration to connect to the servers of the domain
+func newTLSConfig(d *domain.Domain, svids x509svid.Source, bundles x509bundle.Source) (*tls.Config, error) {
+	var auth, err = authorizer(d)
+	if err != nil {
+		return nil, err
+	}
+	var config = tlsconfig.MTLSClientConfig(svids, bundles, auth)
+	if config.MinVersion, err = d.MinTLSVersion(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// workloadAPISocket returns the address of the workload API for the domain
+func workloadAPISocket(d *domain.Domain) string {
+	if d.WorkloadAPISocket != "" {
+		return d.WorkloadAPISocket
+	}
+	if addr, ok := os.LookupEnv(workloadapi.SocketEnv); ok && addr != "" {
+		return addr
+	}
+	return defaultWorkloadAPISocket
+}
+
+// authorizer authorizes servers of the domain by the SPIFFE ID or the trust domain, any server is authorized if none is set
+func authorizer(d *domain.Domain) (tlsconfig.Authorizer, error) {
+	switch {
+	case d.ServerID != "":
+		var id, err = spiffeid.FromString(d.ServerID)
+		if err != nil {
+			return nil, err
+		}
+		return tlsconfig.AuthorizeID(id), nil
+	case d.TrustDomain != "":
+		var td, err = spiffeid.TrustDomainFromString(d.TrustDomain)
+		if err != nil {
+			return nil, err
+		}
+		return tlsconfig.AuthorizeMemberOf(td), nil
+	default:
+		return tlsconfig.AuthorizeAny(), nil
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/grpcmetadata"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+)
+
+// NetworkServiceEndpoints works with network service endpoints of the domain registry
+type NetworkServiceEndpoints struct {
+	client *Client
+}
+
+func registryNetworkServiceEndpointClient(cc grpc.ClientConnInterface) registry.NetworkServiceEndpointRegistryClient {
+	return next.NewNetworkServiceEndpointRegistryClient(
+		grpcmetadata.NewNetworkServiceEndpointRegistryClient(),
+		registry.NewNetworkServiceEndpointRegistryClient(cc),
+	)
+}
+
+// Get returns the network service endpoint with the name, *NotFoundError if there is no such network service endpoint
+func (s *NetworkServiceEndpoints) Get(ctx context.Context, name string) (*registry.NetworkServiceEndpoint, error) {
+	var list, err = s.List(ctx, &registry.NetworkServiceEndpoint{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	// the registry matches names of the endpoints by substring
+	for _, nse := range list {
+		if nse.GetName() == name {
+			return nse, nil
+		}
+	}
+	return nil, &NotFoundError{Kind: "networkserviceendpoint", Name: name}
+}
+
+// List returns network service endpoints that match the query, all network service endpoints if the query is nil
+func (s *NetworkServiceEndpoints) List(ctx context.Context, query *registry.NetworkServiceEndpoint) ([]*registry.NetworkServiceEndpoint, error) {
+	if query == nil {
+		query = new(registry.NetworkServiceEndpoint)
+	}
+
+	var result []*registry.NetworkServiceEndpoint
+	var err = s.client.retry(ctx, s.client.domain.RegistryService, func(ctx context.Context, cc grpc.ClientConnInterface) error {
+		var stream, findErr = registryNetworkServiceEndpointClient(cc).Find(ctx, &registry.NetworkServiceEndpointQuery{NetworkServiceEndpoint: query})
+		if findErr != nil {
+			return findErr
+		}
+		var responses, readErr = readAll(stream.Recv)
+		result = nil
+		for _, resp := range responses {
+			if !resp.GetDeleted() {
+				result = append(result, resp.GetNetworkServiceEndpoint())
+			}
+		}
+		return readErr
+	})
+	return result, err
+}
+
+// Watch calls the handler for the network service endpoints that match the query and for all their changes
+// until the context is done or the handler fails
+func (s *NetworkServiceEndpoints) Watch(ctx context.Context, query *registry.NetworkServiceEndpoint, handler func(*registry.NetworkServiceEndpointResponse) error) error {
+	var d = s.client.domain
+	var cc, err = s.client.dialer.dial(ctx, d, d.RegistryService)
+	if err != nil {
+		return err
+	}
+	if query == nil {
+		query = new(registry.NetworkServiceEndpoint)
+	}
+
+	stream, err := registryNetworkServiceEndpointClient(cc).Find(ctx, &registry.NetworkServiceEndpointQuery{NetworkServiceEndpoint: query, Watch: true})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, recvErr := stream.Recv()
+		if recvErr != nil {
+			return closed(ctx, recvErr)
+		}
+		if err = handler(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// Register registers or updates the network service endpoint
+func (s *NetworkServiceEndpoints) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	var result *registry.NetworkServiceEndpoint
+	var err = s.client.request(ctx, s.client.domain.RegistryService, func(ctx context.Context, cc grpc.ClientConnInterface) error {
+		var registerErr error
+		result, registerErr = registryNetworkServiceEndpointClient(cc).Register(ctx, nse)
+		return registerErr
+	})
+	return result, err
+}
+
+// Unregister removes the network service endpoint with the name
+func (s *NetworkServiceEndpoints) Unregister(ctx context.Context, name string) error {
+	return s.client.request(ctx, s.client.domain.RegistryService, func(ctx context.Context, cc grpc.ClientConnInterface) error {
+		var _, unregisterErr = registryNetworkServiceEndpointClient(cc).Unregister(ctx, &registry.NetworkServiceEndpoint{Name: name})
+		return unregisterErr
+	})
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/grpcmetadata"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+)
+
+// NetworkServices works with network services of the domain registry
+type NetworkServices struct {
+	client *Client
+}
+
+func registryNetworkServiceClient(cc grpc.ClientConnInterface) registry.NetworkServiceRegistryClient {
+	return next.NewNetworkServiceRegistryClient(
+		grpcmetadata.NewNetworkServiceRegistryClient(),
+		registry.NewNetworkServiceRegistryClient(cc),
+	)
+}
+
+// Get returns the network service with the name, *NotFoundError if there is no such network service
+func (s *NetworkServices) Get(ctx context.Context, name string) (*registry.NetworkService, error) {
+	var list, err = s.List(ctx, &registry.NetworkService{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range list {
+		if ns.GetName() == name {
+			return ns, nil
+		}
+	}
+	return nil, &NotFoundError{Kind: "networkservice", Name: name}
+}
+
+// List returns network services that match the query, all network services if the query is nil
+func (s *NetworkServices) List(ctx context.Context, query *registry.NetworkService) ([]*registry.NetworkService, error) {
+	if query == nil {
+		query = new(registry.NetworkService)
+	}
+
+	var result []*registry.NetworkService
+	var err = s.client.retry(ctx, s.client.domain.RegistryService, func(ctx context.Context, cc grpc.ClientConnInterface) error {
+		var stream, findErr = registryNetworkServiceClient(cc).Find(ctx, &registry.NetworkServiceQuery{NetworkService: query})
+		if findErr != nil {
+			return findErr
+		}
+		var responses, readErr = readAll(stream.Recv)
+		result = nil
+		for _, resp := range responses {
+			if !resp.GetDeleted() {
+				result = append(result, resp.GetNetworkService())
+			}
+		}
+		return readErr
+	})
+	return result, err
+}
+
+// Watch calls the handler for the network services that match the query and for all their changes
+// until the context is done or the handler fails
+func (s *NetworkServices) Watch(ctx context.Context, query *registry.NetworkService, handler func(*registry.NetworkServiceResponse) error) error {
+	var d = s.client.domain
+	var cc, err = s.client.dialer.dial(ctx, d, d.RegistryService)
+	if err != nil {
+		return err
+	}
+	if query == nil {
+		query = new(registry.NetworkService)
+	}
+
+	stream, err := registryNetworkServiceClient(cc).Find(ctx, &registry.NetworkServiceQuery{NetworkService: query, Watch: true})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, recvErr := stream.Recv()
+		if recvErr != nil {
+			return closed(ctx, recvErr)
+		}
+		if err = handler(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// Register registers or updates the network service
+func (s *NetworkServices) Register(ctx context.Context, ns *registry.NetworkService) (*registry.NetworkService, error) {
+	var result *registry.NetworkService
+	var err = s.client.request(ctx, s.client.domain.RegistryService, func(ctx context.Context, cc grpc.ClientConnInterface) error {
+		var registerErr error
+		result, registerErr = registryNetworkServiceClient(cc).Register(ctx, ns)
+		return registerErr
+	})
+	return result, err
+}
+
+// Unregister removes the network service with the name
+func (s *NetworkServices) Unregister(ctx context.Context, name string) error {
+	return s.client.request(ctx, s.client.domain.RegistryService, func(ctx context.Context, cc grpc.ClientConnInterface) error {
+		var _, unregisterErr = registryNetworkServiceClient(cc).Unregister(ctx, &registry.NetworkService{Name: name})
+		return unregisterErr
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"time"
+)
+
+// Defaults of the options of the client
+const (
+	DefaultDialTimeout  = 15 * time.Second
+	DefaultRetryBackoff = 500 * time.Millisecond
+	maxRetryBackoff     = 10 * time.Second
+)
+
+// Option configures the client
+type Option func(o *options)
+
+type options struct {
+	dialTimeout    time.Duration
+	requestTimeout time.Duration
+	retries        int
+	retryBackoff   time.Duration
+}
+
+// WithDialTimeout limits connecting to a service of the domain: DNS lookups, getting the X509-SVID and the connection itself.
+// Zero means no limit.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.dialTimeout = timeout
+	}
+}
+
+// WithRequestTimeout limits each request to the domain, watches are not limited. Zero means no limit.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.requestTimeout = timeout
+	}
+}
+
+// WithRetry retries reading requests (get and list) that fail to connect or fail with codes.Unavailable up to the number of retries.
+// The first retry waits for the backoff, each next one waits twice as long up to 10 seconds.
+func WithRetry(retries int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.retries = retries
+		o.retryBackoff = backoff
+	}
+}
+
+func newOptions(opts []Option) *options {
+	var result = &options{
+		dialTimeout:  DefaultDialTimeout,
+		retryBackoff: DefaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(result)
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// request calls the service of the domain once
+func (c *Client) request(ctx context.Context, service string, f func(ctx context.Context, cc grpc.ClientConnInterface) error) error {
+	return c.call(ctx, service, 0, f)
+}
+
+// retry calls the service of the domain until the call succeeds, fails with an error other than codes.Unavailable
+// or the retries are over. Failed dials are retried as well, each attempt dials again. It must be used for reading requests only.
+func (c *Client) retry(ctx context.Context, service string, f func(ctx context.Context, cc grpc.ClientConnInterface) error) error {
+	return c.call(ctx, service, c.options.retries, f)
+}
+
+func (c *Client) call(ctx context.Context, service string, retries int, f func(ctx context.Context, cc grpc.ClientConnInterface) error) error {
+	var backoff = c.options.retryBackoff
+	for attempt := 0; ; attempt++ {
+		var err = c.attempt(ctx, service, f)
+		if err == nil || attempt >= retries || !retryable(err) {
+			return err
+		}
+
+		var timer = time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrapf(err, "request to %v of the domain %v has been retried %v times", service, c.domain.Name, attempt)
+		case <-timer.C:
+		}
+
+		if backoff *= 2; backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// attempt dials the service and calls it with the request timeout, errors of the expired or canceled context tell what has happened.
+// The dial is limited by the dial timeout only.
+func (c *Client) attempt(ctx context.Context, service string, f func(ctx context.Context, cc grpc.ClientConnInterface) error) error {
+	var cc, err = c.dialer.dial(ctx, c.domain, service)
+	if err != nil {
+		return &dialError{err: err}
+	}
+
+	var requestCtx, cancel = ctx, context.CancelFunc(func() {})
+	if c.options.requestTimeout > 0 {
+		requestCtx, cancel = context.WithTimeout(ctx, c.options.requestTimeout)
+	}
+	defer cancel()
+
+	err = f(requestCtx, cc)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(ctx.Err(), context.Canceled):
+		return errors.Wrapf(err, "request to %v of the domain %v has been canceled", service, c.domain.Name)
+	case ctx.Err() != nil:
+		return errors.Wrapf(err, "request to %v of the domain %v timed out", service, c.domain.Name)
+	case requestCtx.Err() != nil:
+		return errors.Wrapf(err, "request to %v of the domain %v timed out after %v", service, c.domain.Name, c.options.requestTimeout)
+	default:
+		return err
+	}
+}
+
+// dialError is the error of connecting to the service, the connection is not kept so the next attempt dials again
+type dialError struct {
+	err error
+}
+
+func (e *dialError) Error() string {
+	return e.err.Error()
+}
+
+func (e *dialError) Unwrap() error {
+	return e.err
+}
+
+// retryable returns true if the error is of the dial or codes.Unavailable
+func retryable(err error) bool {
+	var d *dialError
+	return errors.As(err, &d) || status.Code(errors.Cause(err)) == codes.Unavailable
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/resolver"
+
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+const (
+	// srvScheme is the scheme of the gRPC targets that are resolved by SRV records of the domain
+	srvScheme = "nsm-srv"
+	// minResolveInterval limits how often the service is resolved again when gRPC asks for it
+	minResolveInterval = time.Second
+)
+
+// isAddress returns true if the service of the domain is a 'host:port' address rather than a service name
+func isAddress(service string) bool {
+	return strings.Contains(service, ":")
+}
+
+// newResolver creates a resolver that uses the DNS server of the domain if it is set
+func newResolver(d *domain.Domain) *net.Resolver {
+	var netDialer net.Dialer
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			if d.DNSServerAddress != "" {
+				return netDialer.DialContext(ctx, network, d.DNSServerAddress)
+			}
+			return netDialer.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// lookupSRV returns the SRV records of the service ordered by priority and randomized by weight within a priority.
+// Records with the '.' target mean that the service is not available there and are skipped.
+func lookupSRV(ctx context.Context, r *net.Resolver, serviceDomain string) ([]*net.SRV, error) {
+	_, records, err := r.LookupSRV(ctx, "", "", serviceDomain)
+	if err != nil {
+		return nil, err
+	}
+	var result []*net.SRV
+	for _, record := range records {
+		if record.Target != "." {
+			result = append(result, record)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.Errorf("no SRV records of %v", serviceDomain)
+	}
+	return result, nil
+}
+
+// lookupAddresses returns 'host:port' addresses of every target of the records in the order of the records.
+// Targets that can't be resolved are skipped, the error is returned only if none of them is resolved.
+func lookupAddresses(ctx context.Context, r *net.Resolver, records []*net.SRV) ([]string, error) {
+	var result []string
+	var lookupErr error
+	for _, record := range records {
+		var ips, err = r.LookupIPAddr(ctx, record.Target)
+		if err != nil {
+			if lookupErr == nil {
+				lookupErr = err
+			}
+			continue
+		}
+		for _, ip := range ips {
+			result = append(result, net.JoinHostPort(ip.String(), strconv.Itoa(int(record.Port))))
+		}
+	}
+	if len(result) > 0 {
+		return result, nil
+	}
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+	return nil, errors.New("targets of the SRV records have no addresses")
+}
+
+// resolveService returns addresses of all the replicas of the service of the domain, preferred ones go first
+func resolveService(ctx context.Context, d *domain.Domain, service string) ([]string, error) {
+	var r = newResolver(d)
+	var records, err = lookupSRV(ctx, r, d.FQDN(service))
+	if err != nil {
+		return nil, err
+	}
+	return lookupAddresses(ctx, r, records)
+}
+
+// srvResolverBuilder builds gRPC resolvers of the service of the domain. gRPC gets every address of every SRV target,
+// the pick_first balancer connects to the preferred replica and fails over to the next ones in the order of priorities.
+type srvResolverBuilder struct {
+	domain    *domain.Domain
+	service   string
+	addresses []string
+}
+
+func newSRVResolverBuilder(d *domain.Domain, service string, addresses []string) *srvResolverBuilder {
+	return &srvResolverBuilder{domain: d, service: service, addresses: addresses}
+}
+
+// target returns the gRPC target of the service
+func (b *srvResolverBuilder) target() string {
+	return srvScheme + ":///" + b.service
+}
+
+func (b *srvResolverBuilder) Scheme() string {
+	return srvScheme
+}
+
+// Build starts with the addresses resolved before dialing and resolves the service again each time gRPC asks for it
+func (b *srvResolverBuilder) Build(_ resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	var ctx, cancel = context.WithCancel(context.Background())
+	var r = &srvResolver{
+		builder:    b,
+		cc:         cc,
+		cancel:     cancel,
+		resolveNow: make(chan struct{}, 1),
+	}
+	if err := cc.UpdateState(resolver.State{Addresses: addressesOf(b.addresses)}); err != nil {
+		cancel()
+		return nil, err
+	}
+	r.wg.Add(1)
+	go r.watch(ctx)
+	return r, nil
+}
+
+type srvResolver struct {
+	builder    *srvResolverBuilder
+	cc         resolver.ClientConn
+	cancel     context.CancelFunc
+	resolveNow chan struct{}
+	wg         sync.WaitGroup
+}
+
+func (r *srvResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.resolveNow <- struct{}{}:
+	default:
+	}
+}
+
+func (r *srvResolver) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *srvResolver) watch(ctx context.Context) {
+	defer r.wg.Done()
+	for {
+		var timer = time.NewTimer(minResolveInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.resolveNow:
+		}
+
+		var addresses, err = resolveService(ctx, r.builder.domain, r.builder.service)
+		if err != nil {
+			r.cc.ReportError(err)
+			continue
+		}
+		_ = r.cc.UpdateState(resolver.State{Addresses: addressesOf(addresses)})
+	}
+}
+
+func addressesOf(addresses []string) []resolver.Address {
+	var result []resolver.Address
+	for _, addr := range addresses {
+		result = append(result, resolver.Address{Addr: addr})
+	}
+	return result
+}
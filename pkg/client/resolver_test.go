@@ -0,0 +1,162 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package client_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/nsmctl/pkg/client"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+// serveDNS starts an in-process DNS server with the records, returns its address
+func serveDNS(t *testing.T, records ...string) string {
+	var zone []dns.RR
+	for _, record := range records {
+		var rr, err = dns.NewRR(record)
+		require.NoError(t, err)
+		zone = append(zone, rr)
+	}
+
+	var handler = dns.HandlerFunc(func(w dns.ResponseWriter, msg *dns.Msg) {
+		var resp = new(dns.Msg)
+		resp.SetReply(msg)
+		resp.Authoritative = true
+		for _, q := range msg.Question {
+			for _, rr := range zone {
+				if rr.Header().Name == q.Name && rr.Header().Rrtype == q.Qtype {
+					resp.Answer = append(resp.Answer, rr)
+				}
+			}
+		}
+		if len(resp.Answer) == 0 {
+			resp.SetRcode(msg, dns.RcodeNameError)
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	var conn, err = net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	var listener net.Listener
+	listener, err = net.Listen("tcp", conn.LocalAddr().String())
+	require.NoError(t, err)
+
+	for _, server := range []*dns.Server{{PacketConn: conn, Handler: handler}, {Listener: listener, Handler: handler}} {
+		var server = server
+		go func() { _ = server.ActivateAndServe() }()
+		t.Cleanup(func() { _ = server.Shutdown() })
+	}
+
+	return conn.LocalAddr().String()
+}
+
+func TestClient_SRVFailover(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var closed, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	var closedPort = strconv.Itoa(closed.Addr().(*net.TCPAddr).Port)
+	require.NoError(t, closed.Close())
+
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	require.NoError(t, err)
+	var port = strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+
+	var server = grpc.NewServer()
+	registry.RegisterNetworkServiceRegistryServer(server, new(unavailableRegistry))
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	var dnsServer = serveDNS(t,
+		"registry.nsm-system.test. 60 IN SRV 10 100 "+port+" replica-2.test.",
+		"registry.nsm-system.test. 60 IN SRV 0 100 "+closedPort+" replica-1.test.",
+		"replica-1.test. 60 IN A 127.0.0.1",
+		"replica-2.test. 60 IN AAAA ::1",
+	)
+
+	var c = client.New(&domain.Domain{
+		Name:             "test",
+		DNSServerAddress: dnsServer,
+		RegistryService:  "registry.nsm-system",
+		IsInsecure:       true,
+	})
+	defer func() { require.NoError(t, c.Close()) }()
+
+	list, err := c.NetworkServices().List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	var details = make(map[string]string)
+	for _, s := range c.Check(ctx, time.Second) {
+		if s.Service == "registry.nsm-system" {
+			require.NoError(t, s.Err, s.Name)
+			details[s.Name] = s.Detail
+		}
+	}
+	require.Equal(t, "127.0.0.1:"+closedPort+", [::1]:"+port, details[client.StageIP])
+	require.Equal(t, "[::1]:"+port+", 1 preferred replicas are unreachable", details[client.StageTCP])
+}
+
+func TestClient_SRVNotFound(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var c = client.New(&domain.Domain{
+		Name:             "test",
+		DNSServerAddress: serveDNS(t),
+		RegistryService:  "registry.nsm-system",
+		IsInsecure:       true,
+	})
+	defer func() { require.NoError(t, c.Close()) }()
+
+	_, err := c.NetworkServices().List(ctx, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "DNS lookup of registry.nsm-system of the domain test failed")
+}
+
+func TestClient_CheckDNSServer(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var conn, err = net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	var closedAddress = conn.LocalAddr().String()
+	require.NoError(t, conn.Close())
+
+	for address, expected := range map[string]bool{serveDNS(t): true, closedAddress: false} {
+		var c = client.New(&domain.Domain{Name: "test", DNSServerAddress: address, RegistryService: "registry", ManagerService: "nsmgr", IsInsecure: true})
+		var stages = c.Check(ctx, time.Second)
+		require.NoError(t, c.Close())
+
+		require.Equal(t, client.StageDNSServer, stages[0].Name)
+		require.Equal(t, expected, stages[0].Err == nil, "%v: %v", address, stages[0].Err)
+		require.Equal(t, expected, stages[1].Name == client.StageSRV && !stages[1].Skipped)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/persistence"
+)
+
+// Context combines a domain with identity settings and output preferences, like contexts of kubeconfig.
+// Empty settings of the context keep the settings of the domain.
+type Context struct {
+	Name   string
+	Domain string
+	// WorkloadAPISocket, CertFile, KeyFile, CAFile, ServerID and TrustDomain override the identity settings of the domain
+	WorkloadAPISocket string
+	CertFile          string
+	KeyFile           string
+	CAFile            string
+	ServerID          string
+	TrustDomain       string
+	// Output is the default output format of the commands, e.g. yaml or wide
+	Output string
+}
+
+func (c *Context) String() string {
+	return "NSM Context " + c.Name
+}
+
+// Apply returns a copy of the domain with the settings of the context. The identity source and the authorization
+// of servers set by the context replace the ones of the domain, e.g. certificate files of the context clear
+// the workload API socket of the domain and the trust domain clears the server SPIFFE ID.
+func (c *Context) Apply(d *Domain) *Domain {
+	var result = *d
+	for _, o := range []struct{ value, target *string }{
+		{&c.WorkloadAPISocket, &result.WorkloadAPISocket},
+		{&c.CertFile, &result.CertFile},
+		{&c.KeyFile, &result.KeyFile},
+		{&c.CAFile, &result.CAFile},
+		{&c.ServerID, &result.ServerID},
+		{&c.TrustDomain, &result.TrustDomain},
+	} {
+		if *o.value != "" {
+			*o.target = *o.value
+		}
+	}
+	if c.WorkloadAPISocket != "" {
+		result.CertFile, result.KeyFile, result.CAFile = "", "", ""
+	}
+	if c.CertFile != "" {
+		result.WorkloadAPISocket = ""
+	}
+	if c.ServerID != "" {
+		result.TrustDomain = ""
+	}
+	if c.TrustDomain != "" {
+		result.ServerID = ""
+	}
+	return &result
+}
+
+// Resolve returns the domain of the context with the settings of the context
+func (c *Context) Resolve() (*Domain, error) {
+	var d, err = Load(c.Domain)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load domain %v of context %v", c.Domain, c.Name)
+	}
+	d = c.Apply(d)
+	if err = d.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "context %v", c.Name)
+	}
+	return d, nil
+}
+
+// Contexts returns all the stored contexts sorted by name
+func Contexts() ([]*Context, error) {
+	return persistence.List[*Context]()
+}
+
+// CurrentContext returns the current context, nil if there is no current context
+func CurrentContext() (*Context, error) {
+	var name, err = persistence.Current[*Context]()
+	if err != nil || name == "" {
+		return nil, err
+	}
+	return persistence.Load[*Context](name)
+}
+
+// StoreContext stores the context
+func StoreContext(c *Context) error {
+	return persistence.Store(c.Name, c)
+}
+
+// UseContext makes the context with the name current
+func UseContext(name string) error {
+	return persistence.ModifyCurrent(func(contexts map[string]*Context, current *string) error {
+		if _, ok := contexts[name]; !ok {
+			return errors.Errorf("context %v is not found", name)
+		}
+		*current = name
+		return nil
+	})
+}
+
+// DeleteContext deletes the context with the name. Deleting the current context leaves no context current.
+func DeleteContext(name string) error {
+	return persistence.Modify(func(contexts map[string]*Context) error {
+		if _, ok := contexts[name]; !ok {
+			return errors.Errorf("context %v is not found", name)
+		}
+		delete(contexts, name)
+		return nil
+	})
+}
+
+// RenameContext renames the context, the context stays current if it is
+func RenameContext(name, newName string) error {
+	return persistence.ModifyCurrent(func(contexts map[string]*Context, current *string) error {
+		var c, ok = contexts[name]
+		if !ok {
+			return errors.Errorf("context %v is not found", name)
+		}
+		if _, ok = contexts[newName]; ok {
+			return errors.Errorf("context %v already exists", newName)
+		}
+		delete(contexts, name)
+		c.Name = newName
+		contexts[newName] = c
+		if *current == name {
+			*current = newName
+		}
+		return nil
+	})
+}
+
+// clearCurrentContext leaves no context current
+func clearCurrentContext() error {
+	return persistence.ModifyCurrent(func(_ map[string]*Context, current *string) error {
+		*current = ""
+		return nil
+	})
+}
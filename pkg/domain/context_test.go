@@ -0,0 +1,51 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+func TestContext_Apply(t *testing.T) {
+	var d = domain.New("my-domain")
+	d.WorkloadAPISocket, d.TrustDomain = "unix:///agent.sock", "example.org"
+
+	var c = &domain.Context{Name: "files", CertFile: "svid.pem", KeyFile: "key.pem", CAFile: "bundle.pem", ServerID: "spiffe://example.org/nsmgr"}
+	var applied = c.Apply(d)
+	require.NoError(t, applied.Validate())
+	require.Empty(t, applied.WorkloadAPISocket)
+	require.Empty(t, applied.TrustDomain)
+	require.Equal(t, "svid.pem", applied.CertFile)
+	require.Equal(t, "spiffe://example.org/nsmgr", applied.ServerID)
+	require.Equal(t, "unix:///agent.sock", d.WorkloadAPISocket)
+
+	c = &domain.Context{Name: "socket", WorkloadAPISocket: "unix:///other.sock", TrustDomain: "example.com"}
+	applied = c.Apply(applied)
+	require.NoError(t, applied.Validate())
+	require.Empty(t, applied.CertFile)
+	require.Empty(t, applied.KeyFile)
+	require.Empty(t, applied.CAFile)
+	require.Empty(t, applied.ServerID)
+	require.Equal(t, "example.com", applied.TrustDomain)
+
+	applied = (&domain.Context{Name: "empty"}).Apply(d)
+	require.Equal(t, d, applied)
+}
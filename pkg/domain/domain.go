@@ -0,0 +1,340 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package domain contains implementation of NSM domain
+package domain
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"gopkg.in/yaml.v2"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/persistence"
+)
+
+var current *Domain
+
+// Domain represents environment where is running NSM instance what we want to connect.
+// JSON and YAML names of the fields follow protobuf naming, so field selectors, sorting and JSONPath use the same names
+// as for the other resources, e.g. .registryService, and the printed domains can be applied back.
+type Domain struct {
+	Name             string `json:"name" yaml:"name"`
+	DNSServerAddress string `json:"dnsServerAddress" yaml:"dnsServerAddress"`
+	RegistryService  string `json:"registryService" yaml:"registryService"`
+	ManagerService   string `json:"managerService" yaml:"managerService"`
+	Path             string `json:"path" yaml:"path"`
+	IsDefault        bool   `json:"isDefault" yaml:"isDefault"`
+	IsInsecure       bool   `json:"isInsecure" yaml:"isInsecure"`
+	// WorkloadAPISocket is the address of the SPIFFE workload API that provides the identity, e.g. unix:///run/spire/sockets/agent.sock.
+	// SPIFFE_ENDPOINT_SOCKET or unix:///tmp/spire-agent/public/api.sock is used if empty.
+	WorkloadAPISocket string `json:"workloadApiSocket" yaml:"workloadApiSocket"`
+	// CertFile and KeyFile are PEM files of the X509-SVID that is used instead of the workload API
+	CertFile string `json:"certFile" yaml:"certFile"`
+	KeyFile  string `json:"keyFile" yaml:"keyFile"`
+	// CAFile is the PEM bundle of the trust domain of the servers, required with CertFile
+	CAFile string `json:"caFile" yaml:"caFile"`
+	// ServerID is the SPIFFE ID the servers of the domain must have, e.g. spiffe://example.org/nsmgr
+	ServerID string `json:"serverId" yaml:"serverId"`
+	// TrustDomain is the trust domain the servers of the domain must belong to, used if ServerID is empty.
+	// Any server is accepted if both are empty.
+	TrustDomain string `json:"trustDomain" yaml:"trustDomain"`
+	// TokenLifetime is the lifetime of JWT tokens sent with requests as a duration, e.g. 30m, one hour if empty
+	TokenLifetime string `json:"tokenLifetime" yaml:"tokenLifetime"`
+	// TLSMinVersion is the minimum TLS version, 1.2 or 1.3. 1.2 is used if empty.
+	TLSMinVersion string `json:"tlsMinVersion" yaml:"tlsMinVersion"`
+}
+
+// DefaultTokenLifetime is the lifetime of JWT tokens if the domain doesn't set it
+const DefaultTokenLifetime = time.Hour
+
+// tlsVersions maps supported values of TLSMinVersion to the TLS versions
+var tlsVersions = map[string]uint16{
+	"":    tls.VersionTLS12,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// SetCurrent replaces the current NSM domain
+func SetCurrent(d *Domain) {
+	current = d
+}
+
+// Load loads the stored domain by name
+func Load(name string) (*Domain, error) {
+	return persistence.Load[*Domain](name)
+}
+
+type contextKey struct{}
+
+// WithContext returns a context that selects the domain instead of the current one
+func WithContext(ctx context.Context, d *Domain) context.Context {
+	return context.WithValue(ctx, contextKey{}, d)
+}
+
+// FromContext returns the domain selected by the context or the current domain if the context selects none
+func FromContext(ctx context.Context) (*Domain, error) {
+	if d, ok := ctx.Value(contextKey{}).(*Domain); ok {
+		return d, nil
+	}
+	return Current()
+}
+
+// List returns all the stored domains sorted by name
+func List() ([]*Domain, error) {
+	return persistence.List[*Domain]()
+}
+
+// Store stores the domain. If the domain is default, the other domains stop being default in the same update.
+func Store(d *Domain) error {
+	return persistence.Modify(func(domains map[string]*Domain) error {
+		if d.IsDefault {
+			for _, other := range domains {
+				other.IsDefault = false
+			}
+		}
+		domains[d.Name] = d
+		return nil
+	})
+}
+
+// Use makes the stored domain with the name default. The current context is cleared, so the domain is used.
+func Use(name string) error {
+	if _, err := Load(name); err != nil {
+		return errors.Errorf("domain %v is not found", name)
+	}
+	if err := clearCurrentContext(); err != nil {
+		return err
+	}
+	return persistence.Modify(func(domains map[string]*Domain) error {
+		if _, ok := domains[name]; !ok {
+			return errors.Errorf("domain %v is not found", name)
+		}
+		for _, d := range domains {
+			d.IsDefault = d.Name == name
+		}
+		return nil
+	})
+}
+
+// Current returns current NSM domain: the domain set by SetCurrent, the domain of the current context or the default domain
+func Current() (*Domain, error) {
+	if current != nil {
+		return current, nil
+	}
+
+	var c, err = CurrentContext()
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		return c.Resolve()
+	}
+
+	var domains []*Domain
+	if domains, err = List(); err != nil {
+		return nil, err
+	}
+
+	var result []*Domain
+	for _, d := range domains {
+		if d.IsDefault {
+			result = append(result, d)
+		}
+	}
+
+	if len(result) != 1 {
+		return nil, errors.New("something went wrong with domains, please use 'nsmctl use domain $DOMAIN_NAME'")
+	}
+
+	return result[0], nil
+}
+
+// UnmarshalYAML decodes the domain. The field names in lower case and the token lifetime in nanoseconds
+// that older versions of nsmctl wrote are accepted too, e.g. registryservice.
+func (d *Domain) UnmarshalYAML(unmarshal func(any) error) error {
+	var fields map[string]any
+	if err := unmarshal(&fields); err != nil {
+		return err
+	}
+	var t = reflect.TypeOf(*d)
+	for i := 0; i < t.NumField(); i++ {
+		var name = t.Field(i).Tag.Get("yaml")
+		var legacy = strings.ToLower(t.Field(i).Name)
+		if v, ok := fields[legacy]; ok && legacy != name {
+			if _, set := fields[name]; !set {
+				fields[name] = v
+			}
+			delete(fields, legacy)
+		}
+	}
+
+	if n, ok := fields["tokenLifetime"].(int); ok {
+		fields["tokenLifetime"] = time.Duration(n).String()
+	}
+
+	var b, err = yaml.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	// plain has the fields of Domain without its methods, so the decoding doesn't recurse
+	type plain Domain
+	return yaml.Unmarshal(b, (*plain)(d))
+}
+
+func (d *Domain) String() string {
+	return "NSM Domain " + d.Name
+}
+
+// New creates a new domain
+func New(name string) *Domain {
+	return &Domain{
+		Name:            name,
+		ManagerService:  "nsmgr-proxy.nsm-system",
+		RegistryService: "registry.nsm-system",
+	}
+}
+
+// FQDN returns a fully qualified domain name of the service
+func (d *Domain) FQDN(service string) string {
+	return fmt.Sprintf("%v.%v.", service, d.Name)
+}
+
+// dnsLabel matches a label of a DNS name, e.g. nsm-system
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// Validate checks that the domain has a valid name and the services are either 'host:port' addresses
+// or service names that are resolved in the domain, e.g. registry.nsm-system
+func (d *Domain) Validate() error {
+	if err := validateName(d.Name); err != nil {
+		return errors.Wrap(err, "invalid domain name")
+	}
+	if err := validateService(d.RegistryService); err != nil {
+		return errors.Wrap(err, "invalid registry service")
+	}
+	if err := validateService(d.ManagerService); err != nil {
+		return errors.Wrap(err, "invalid manager service")
+	}
+	if d.DNSServerAddress != "" {
+		if err := validateAddress(d.DNSServerAddress); err != nil {
+			return errors.Wrap(err, "invalid DNS server address")
+		}
+	}
+	return d.validateIdentity()
+}
+
+func (d *Domain) validateIdentity() error {
+	switch {
+	case (d.CertFile == "") != (d.KeyFile == ""):
+		return errors.New("certificate and key files must be set together")
+	case d.CertFile != "" && d.CAFile == "":
+		return errors.New("CA file is required with the certificate")
+	case d.CertFile == "" && d.CAFile != "":
+		return errors.New("CA file can be used with the certificate only")
+	case d.CertFile != "" && d.WorkloadAPISocket != "":
+		return errors.New("certificate files and the workload API socket can not be used together")
+	case d.ServerID != "" && d.TrustDomain != "":
+		return errors.New("server SPIFFE ID and trust domain can not be used together")
+	}
+	if d.ServerID != "" {
+		if _, err := spiffeid.FromString(d.ServerID); err != nil {
+			return errors.Wrap(err, "invalid server SPIFFE ID")
+		}
+	}
+	if d.TrustDomain != "" {
+		if _, err := spiffeid.TrustDomainFromString(d.TrustDomain); err != nil {
+			return errors.Wrap(err, "invalid trust domain")
+		}
+	}
+	if _, err := d.MinTLSVersion(); err != nil {
+		return err
+	}
+	if _, err := d.TokenLifetimeOrDefault(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MinTLSVersion returns the minimum TLS version for connections to the domain
+func (d *Domain) MinTLSVersion() (uint16, error) {
+	var v, ok = tlsVersions[d.TLSMinVersion]
+	if !ok {
+		return 0, errors.Errorf("unsupported TLS version %q, expected 1.2 or 1.3", d.TLSMinVersion)
+	}
+	return v, nil
+}
+
+// TokenLifetimeOrDefault returns the lifetime of JWT tokens for the domain
+func (d *Domain) TokenLifetimeOrDefault() (time.Duration, error) {
+	if d.TokenLifetime == "" {
+		return DefaultTokenLifetime, nil
+	}
+	var lifetime, err = time.ParseDuration(d.TokenLifetime)
+	switch {
+	case err != nil:
+		return 0, errors.Wrap(err, "invalid token lifetime")
+	case lifetime < 0:
+		return 0, errors.Errorf("token lifetime can not be negative, got %v", d.TokenLifetime)
+	case lifetime == 0:
+		return DefaultTokenLifetime, nil
+	}
+	return lifetime, nil
+}
+
+func validateService(s string) error {
+	if s == "" {
+		return errors.New("service is required")
+	}
+	if strings.Contains(s, ":") {
+		return validateAddress(s)
+	}
+	return validateName(s)
+}
+
+func validateAddress(s string) error {
+	var host, port, err = net.SplitHostPort(s)
+	if err != nil {
+		return errors.Wrapf(err, "expected host:port, got %q", s)
+	}
+	if host == "" {
+		return errors.Errorf("host is missing in %q", s)
+	}
+	if n, parseErr := strconv.ParseUint(port, 10, 16); parseErr != nil || n == 0 {
+		return errors.Errorf("invalid port %q in %q", port, s)
+	}
+	return nil
+}
+
+func validateName(s string) error {
+	if s == "" || len(s) > 253 {
+		return errors.Errorf("expected a DNS name, got %q", s)
+	}
+	for _, label := range strings.Split(s, ".") {
+		if !dnsLabel.MatchString(label) {
+			return errors.Errorf("expected a DNS name of lowercase letters, digits, '-' and '.', got %q", s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2023 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/nsmctl/internal/pkg/tools/codec"
+	"github.com/networkservicemesh/nsmctl/pkg/domain"
+)
+
+func TestValidate_Defaults(t *testing.T) {
+	require.NoError(t, domain.New("cluster.local").Validate())
+}
+
+func TestValidate(t *testing.T) {
+	var valid = func(modify func(d *domain.Domain)) error {
+		var d = domain.New("my-domain")
+		modify(d)
+		return d.Validate()
+	}
+
+	require.NoError(t, valid(func(d *domain.Domain) { d.RegistryService = "10.0.0.1:5002" }))
+	require.NoError(t, valid(func(d *domain.Domain) { d.ManagerService = "[::1]:5001" }))
+	require.NoError(t, valid(func(d *domain.Domain) { d.DNSServerAddress = "10.0.0.10:53" }))
+	require.NoError(t, valid(func(d *domain.Domain) { d.CertFile, d.KeyFile, d.CAFile = "svid.pem", "key.pem", "bundle.pem" }))
+	require.NoError(t, valid(func(d *domain.Domain) { d.ServerID = "spiffe://example.org/nsmgr" }))
+	require.NoError(t, valid(func(d *domain.Domain) { d.TrustDomain, d.TLSMinVersion = "example.org", "1.3" }))
+	require.NoError(t, valid(func(d *domain.Domain) { d.TokenLifetime = "30m" }))
+
+	require.Error(t, valid(func(d *domain.Domain) { d.Name = "" }))
+	require.Error(t, valid(func(d *domain.Domain) { d.Name = "My_Domain" }))
+	require.Error(t, valid(func(d *domain.Domain) { d.RegistryService = "" }))
+	require.Error(t, valid(func(d *domain.Domain) { d.RegistryService = "10.0.0.1:" }))
+	require.Error(t, valid(func(d *domain.Domain) { d.ManagerService = ":5001" }))
+	require.Error(t, valid(func(d *domain.Domain) { d.ManagerService = "10.0.0.1:70000" }))
+	require.Error(t, valid(func(d *domain.Domain) { d.DNSServerAddress = "10.0.0.10" }))
+	require.Error(t, valid(func(d *domain.Domain) { d.CertFile, d.CAFile = "svid.pem", "bundle.pem" }))
+	require.Error(t, valid(func(d *domain.Domain) { d.CertFile, d.KeyFile = "svid.pem", "key.pem" }))
+	require.Error(t, valid(func(d *domain.Domain) {
+		d.CertFile, d.KeyFile, d.CAFile, d.WorkloadAPISocket = "svid.pem", "key.pem", "bundle.pem", "unix:///agent.sock"
+	}))
+	require.Error(t, valid(func(d *domain.Domain) { d.ServerID = "nsmgr" }))
+	require.Error(t, valid(func(d *domain.Domain) { d.ServerID, d.TrustDomain = "spiffe://example.org/nsmgr", "example.org" }))
+	require.Error(t, valid(func(d *domain.Domain) { d.TLSMinVersion = "1.1" }))
+	require.Error(t, valid(func(d *domain.Domain) { d.TokenLifetime = "-1m" }))
+	require.Error(t, valid(func(d *domain.Domain) { d.TokenLifetime = "1 hour" }))
+}
+
+func TestEncoding_RoundTrip(t *testing.T) {
+	var d = domain.New("my-domain")
+	d.DNSServerAddress, d.IsInsecure, d.ServerID, d.TokenLifetime = "10.0.0.10:53", true, "spiffe://example.org/nsmgr", "2h"
+
+	for _, marshal := range []func(any) ([]byte, error){codec.MarshalJSON, codec.MarshalYAML} {
+		var b, err = marshal(d)
+		require.NoError(t, err)
+		require.Contains(t, string(b), "registryService")
+		require.Contains(t, string(b), "2h")
+
+		var decoded = new(domain.Domain)
+		require.NoError(t, codec.UnmarshalYAML(b, decoded))
+		require.Equal(t, d, decoded)
+	}
+}
+
+func TestEncoding_LowerCase(t *testing.T) {
+	var d = new(domain.Domain)
+	require.NoError(t, codec.UnmarshalYAML([]byte("name: my-domain\nregistryservice: registry.nsm-system\nisinsecure: true\ntokenlifetime: 1800000000000\n"), d))
+	require.Equal(t, &domain.Domain{Name: "my-domain", RegistryService: "registry.nsm-system", IsInsecure: true, TokenLifetime: "30m0s"}, d)
+}
+
+func TestTokenLifetimeOrDefault(t *testing.T) {
+	var lifetime, err = (&domain.Domain{}).TokenLifetimeOrDefault()
+	require.NoError(t, err)
+	require.Equal(t, domain.DefaultTokenLifetime, lifetime)
+
+	lifetime, err = (&domain.Domain{TokenLifetime: "30m"}).TokenLifetimeOrDefault()
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Minute, lifetime)
+}